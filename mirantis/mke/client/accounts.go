@@ -0,0 +1,79 @@
+package client
+
+/**
+Account abstractions
+
+eNZi accounts are either users or organizations, which hold teams.
+
+@NOTE this should be implemented using a direct import of enzi client code
+	@see https://github.com/Mirantis/orca/blob/master/enzi/api/forms/accounts.go
+	@see https://github.com/Mirantis/orca/blob/master/enzi/api/responses/responses.go
+*/
+
+// AccountFilter which accounts an account list returns
+type AccountFilter string
+
+const (
+	AccountFilterAll           AccountFilter = "all"
+	AccountFilterUsers         AccountFilter = "users"
+	AccountFilterOrgs          AccountFilter = "orgs"
+	AccountFilterAdmins        AccountFilter = "admins"
+	AccountFilterNonAdmins     AccountFilter = "non-admins"
+	AccountFilterActiveUsers   AccountFilter = "active-users"
+	AccountFilterInactiveUsers AccountFilter = "inactive-users"
+)
+
+// Valid is the filter one that MKE understands
+func (af AccountFilter) Valid() bool {
+	switch af {
+	case AccountFilterAll, AccountFilterUsers, AccountFilterOrgs, AccountFilterAdmins, AccountFilterNonAdmins, AccountFilterActiveUsers, AccountFilterInactiveUsers:
+		return true
+	}
+	return false
+}
+
+// Account api interpretation of a user or organization account
+type Account struct {
+	ID         string `json:"id"         description:"the ID of the account"`
+	Name       string `json:"name"       description:"the name of the account"`
+	FullName   string `json:"fullName"   description:"the full name of the account"`
+	IsOrg      bool   `json:"isOrg"      description:"whether the account is an organization"`
+	IsAdmin    bool   `json:"isAdmin"    description:"whether the user is a system admin"`
+	IsActive   bool   `json:"isActive"   description:"whether the user is active and can log in"`
+	IsImported bool   `json:"isImported" description:"whether the user was imported from LDAP"`
+}
+
+// CreateAccount form to create a user or organization account
+type CreateAccount struct {
+	Name     string `json:"name"`
+	FullName string `json:"fullName,omitempty"`
+	IsOrg    bool   `json:"isOrg"`
+	// user only
+	Password string `json:"password,omitempty"`
+	IsAdmin  bool   `json:"isAdmin"`
+	IsActive bool   `json:"isActive"`
+}
+
+// UpdateAccount form to change an account, only the fields which are set are changed
+type UpdateAccount struct {
+	FullName *string `json:"fullName,omitempty"`
+	// user only
+	IsAdmin  *bool `json:"isAdmin,omitempty"`
+	IsActive *bool `json:"isActive,omitempty"`
+}
+
+// GetAccountsResponse a page of accounts
+type GetAccountsResponse struct {
+	Accounts      []Account `json:"accounts"`
+	NextPageStart string    `json:"nextPageStart"`
+}
+
+// PageLength for the Page interface
+func (gar GetAccountsResponse) PageLength() int {
+	return len(gar.Accounts)
+}
+
+// PageNextStart for the Page interface
+func (gar GetAccountsResponse) PageNextStart() string {
+	return gar.NextPageStart
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// /accounts/
+	URLTargetForAccounts = "accounts/"
+	// /accounts/{accountNameOrID}
+	URLTargetPatternForAccount = "accounts/%s"
+	// /accounts/{accountNameOrID}/changePassword
+	URLTargetPatternForAccountPassword = "accounts/%s/changePassword"
+
+	QueryKeyAccountFilter = "filter"
+)
+
+// ApiAccountList list the accounts which match a filter
+func (c *Client) ApiAccountList(ctx context.Context, filter AccountFilter) ([]Account, error) {
+	if !filter.Valid() {
+		return nil, fmt.Errorf("%w; %s", ErrInvalidFilter, filter)
+	}
+
+	u := URLTargetForAccounts + "?" + url.Values{QueryKeyAccountFilter: {string(filter)}}.Encode()
+
+	var accounts []Account
+
+	pager := c.NewPager(u, NewPageOptionsDefault())
+	for pager.More() {
+		var page GetAccountsResponse
+		if err := pager.Next(ctx, &page); err != nil {
+			return accounts, err
+		}
+		accounts = append(accounts, page.Accounts...)
+	}
+
+	return accounts[:pager.Trim(len(accounts))], nil
+}
+
+// ApiAccountCreate create a user or organization account
+func (c *Client) ApiAccountCreate(ctx context.Context, create CreateAccount) (Account, error) {
+	var a Account
+
+	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPost, URLTargetForAccounts, create)
+	if err != nil {
+		return a, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return a, err
+	}
+
+	if err = resp.JSONMarshallBody(&a); err != nil {
+		return a, err
+	}
+
+	return a, nil
+}
+
+// ApiAccountRetrieve retrieve an account by name or ID
+func (c *Client) ApiAccountRetrieve(ctx context.Context, account string) (Account, error) {
+	u := fmt.Sprintf(URLTargetPatternForAccount, account)
+
+	var a Account
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodGet, u, []byte{})
+	if err != nil {
+		return a, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return a, err
+	}
+
+	if err = resp.JSONMarshallBody(&a); err != nil {
+		return a, err
+	}
+
+	return a, nil
+}
+
+// ApiAccountUpdate change the details of an account
+func (c *Client) ApiAccountUpdate(ctx context.Context, account string, update UpdateAccount) (Account, error) {
+	u := fmt.Sprintf(URLTargetPatternForAccount, account)
+
+	var a Account
+
+	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPatch, u, update)
+	if err != nil {
+		return a, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return a, err
+	}
+
+	if err = resp.JSONMarshallBody(&a); err != nil {
+		return a, err
+	}
+
+	return a, nil
+}
+
+// ApiAccountChangePassword set a new password for a user, as an admin
+func (c *Client) ApiAccountChangePassword(ctx context.Context, account, password string) error {
+	u := fmt.Sprintf(URLTargetPatternForAccountPassword, account)
+
+	change := struct {
+		NewPassword string `json:"newPassword"`
+	}{NewPassword: password}
+
+	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPost, u, change)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doAuthorizedRequest(req)
+	return err
+}
+
+// ApiAccountDelete delete an account
+func (c *Client) ApiAccountDelete(ctx context.Context, account string) error {
+	u := fmt.Sprintf(URLTargetPatternForAccount, account)
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodDelete, u, []byte{})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doAuthorizedRequest(req)
+	return err
+}
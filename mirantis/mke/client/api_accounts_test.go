@@ -0,0 +1,133 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestAccountList(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	mockRequest := MockHandlerKey{
+		Path:   client.URLTargetForAccounts,
+		Method: http.MethodGet,
+	}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		mockRequest: func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get(client.QueryKeyAccountFilter) != string(client.AccountFilterOrgs) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			resp := client.GetAccountsResponse{Accounts: []client.Account{{Name: "org1", IsOrg: true}}}
+			if r.URL.Query().Get(client.QueryKeyPageStart) == "" {
+				resp.NextPageStart = "org2"
+			} else {
+				resp.Accounts[0].Name = "org2"
+			}
+			MockServerHandlerGeneratorReturnJson(resp)(w, r)
+		},
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	accounts, err := c.ApiAccountList(ctx, client.AccountFilterOrgs)
+	if err != nil {
+		t.Fatalf("List accounts failed: %s", err)
+	}
+	if len(accounts) != 2 || accounts[1].Name != "org2" {
+		t.Errorf("Wrong accounts listed: %+v", accounts)
+	}
+
+	if _, err := c.ApiAccountList(ctx, client.AccountFilter("everyone")); !errors.Is(err, client.ErrInvalidFilter) {
+		t.Errorf("Wrong error for an invalid filter: %s", err)
+	}
+}
+
+func TestAccountCreateUpdateDelete(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	account := client.Account{}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		MockHandlerKey{Path: client.URLTargetForAccounts, Method: http.MethodPost}: func(w http.ResponseWriter, r *http.Request) {
+			var create client.CreateAccount
+			if err := json.NewDecoder(r.Body).Decode(&create); err != nil || create.Password == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			account = client.Account{ID: "newid", Name: create.Name, FullName: create.FullName, IsActive: create.IsActive}
+			MockServerHandlerGeneratorReturnJson(account)(w, r)
+		},
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForAccount, "newuser"), Method: http.MethodGet}: func(w http.ResponseWriter, r *http.Request) {
+			MockServerHandlerGeneratorReturnJson(account)(w, r)
+		},
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForAccount, "newuser"), Method: http.MethodPatch}: func(w http.ResponseWriter, r *http.Request) {
+			var update map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&update)
+			if _, ok := update["fullName"]; ok || update["isAdmin"] != true {
+				// only the fields that are set should be sent
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			account.IsAdmin = true
+			MockServerHandlerGeneratorReturnJson(account)(w, r)
+		},
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForAccountPassword, "newuser"), Method: http.MethodPost}: MockServerHandlerGeneratorReturnJson(account),
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForAccount, "newuser"), Method: http.MethodDelete}:       MockServerHandlerGeneratorReturnResponseStatus(http.StatusNoContent),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	created, err := c.ApiAccountCreate(ctx, client.CreateAccount{Name: "newuser", FullName: "New User", Password: "newpassword", IsActive: true})
+	if err != nil {
+		t.Fatalf("Create account failed: %s", err)
+	}
+	if created.ID != "newid" || created.FullName != "New User" || !created.IsActive {
+		t.Errorf("Wrong account created: %+v", created)
+	}
+
+	if retrieved, err := c.ApiAccountRetrieve(ctx, "newuser"); err != nil || retrieved.ID != "newid" {
+		t.Errorf("Retrieve account failed: %+v, %s", retrieved, err)
+	}
+
+	isAdmin := true
+	if updated, err := c.ApiAccountUpdate(ctx, "newuser", client.UpdateAccount{IsAdmin: &isAdmin}); err != nil || !updated.IsAdmin {
+		t.Errorf("Update account failed: %+v, %s", updated, err)
+	}
+
+	if err := c.ApiAccountChangePassword(ctx, "newuser", "otherpassword"); err != nil {
+		t.Errorf("Change password failed: %s", err)
+	}
+
+	if err := c.ApiAccountDelete(ctx, "newuser"); err != nil {
+		t.Errorf("Delete account failed: %s", err)
+	}
+
+	if _, err := c.ApiAccountRetrieve(ctx, "nouser"); !errors.Is(err, client.ErrUnknownTarget) {
+		t.Errorf("Wrong error retrieving a missing account: %s", err)
+	}
+}
@@ -1,13 +1,23 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 const (
 	URLTargetForAuth = "auth/login"
+
+	// eNZi error codes for a login that needs a (valid) two factor code
+	APIErrorCodeMFARequired    = "MFA_REQUIRED"
+	APIErrorCodeInvalidMFACode = "INVALID_MFA_CODE"
 )
 
 // MKE API json response for successful token retrieval
@@ -25,16 +35,47 @@ func (lr loginResponse) Bytes() []byte {
 	return lrb
 }
 
-// apiLogin update client Auth with a new token from an API auth request
+// ApiLogin update client Auth with a new token from an API auth request
 func (c *Client) ApiLogin(ctx context.Context) error {
-	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPost, URLTargetForAuth, c.auth)
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	return c.login(ctx)
+}
+
+// login retrieve a new token, the caller must hold the token lock
+func (c *Client) login(ctx context.Context) error {
+	if !c.auth.canLogin() {
+		return ErrCannotLogin
+	}
+
+	if c.auth.TOTPSecret != "" {
+		code, err := TOTPCode(c.auth.TOTPSecret, time.Now())
+		if err != nil {
+			return err
+		}
+		c.auth.Code = code
+	}
+
+	// logging in has no side effects, so it is retried like an idempotent
+	// request, except that a static two factor code may already have been used
+	// up by the failed attempt, so it is never sent twice.
+	loginCtx := withRetryable(ctx)
+	if c.auth.Code != "" && c.auth.TOTPSecret == "" {
+		loginCtx = withoutRetry(ctx)
+	}
+
+	req, err := c.RequestFromTargetAndJSONBody(loginCtx, http.MethodPost, URLTargetForAuth, c.auth)
 	if err != nil {
 		return err
 	}
+	if c.auth.TOTPSecret != "" {
+		req.GetBody = loginBodyWithTOTPCode(*c.auth)
+	}
 
 	resp, err := c.doRequest(req)
 	if err != nil {
-		return err
+		return loginError(c.auth, err)
 	}
 
 	var loginResp loginResponse
@@ -44,6 +85,41 @@ func (c *Client) ApiLogin(ctx context.Context) error {
 	}
 
 	c.auth.Token = loginResp.Token
+	c.tokenIssued = time.Now()
 
 	return nil
 }
+
+// loginBodyWithTOTPCode login request body for retries, with a fresh two factor
+// code for each attempt
+func loginBodyWithTOTPCode(auth Auth) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		code, err := TOTPCode(auth.TOTPSecret, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		auth.Code = code
+
+		b, err := json.Marshal(auth)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+}
+
+// loginError explain a failed login which needed a two factor code
+func loginError(auth *Auth, err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	if !apiErr.HasCode(APIErrorCodeMFARequired) && !apiErr.HasCode(APIErrorCodeInvalidMFACode) {
+		return err
+	}
+
+	if auth.Code == "" {
+		return fmt.Errorf("%w; %s", ErrMFARequired, err)
+	}
+	return fmt.Errorf("%w; %s", ErrMFACodeRejected, err)
+}
@@ -2,6 +2,7 @@ package client_test
 
 import (
 	"context"
+	"errors"
 	"net/url"
 	"testing"
 
@@ -77,3 +78,49 @@ func TestBadAuthRequest(t *testing.T) {
 		t.Error("Login request did not fail with bad password")
 	}
 }
+
+func TestMFAAuthRequest(t *testing.T) {
+	ctx := context.Background()
+	srvAuth := client.Auth{
+		Username:   "myuser",
+		Password:   "mypassword",
+		Token:      "mytoken",
+		TOTPSecret: "JBSWY3DPEHPK3PXP",
+	}
+
+	svr := MockTestServer(&srvAuth, MockHandlerMap{})
+
+	u, _ := url.Parse(svr.URL)
+
+	clAuthNoCode := client.NewAuthUP(srvAuth.Username, srvAuth.Password)
+	c, err := client.NewClient(u, &clAuthNoCode, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+	if err := c.ApiLogin(ctx); !errors.Is(err, client.ErrMFARequired) {
+		t.Errorf("Login without a two factor code gave the wrong error: %s", err)
+	}
+
+	clAuthBadCode := client.NewAuthUP(srvAuth.Username, srvAuth.Password)
+	clAuthBadCode.Code = "000000"
+	c, err = client.NewClient(u, &clAuthBadCode, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+	if err := c.ApiLogin(ctx); !errors.Is(err, client.ErrMFACodeRejected) {
+		t.Errorf("Login with a bad two factor code gave the wrong error: %s", err)
+	}
+
+	clAuthTOTP := client.NewAuthUP(srvAuth.Username, srvAuth.Password)
+	clAuthTOTP.TOTPSecret = srvAuth.TOTPSecret
+	c, err = client.NewClient(u, &clAuthTOTP, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+	if err := c.ApiLogin(ctx); err != nil {
+		t.Errorf("Login with a TOTP secret failed: %s", err)
+	}
+	if clAuthTOTP.Token != srvAuth.Token {
+		t.Errorf("ApiLogin did not set the expected token: %s != %s", clAuthTOTP.Token, srvAuth.Token)
+	}
+}
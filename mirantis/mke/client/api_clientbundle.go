@@ -1,8 +1,6 @@
 package client
 
 import (
-	"archive/zip"
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -14,20 +12,17 @@ import (
 const (
 	URLTargetForClientBundle = "api/clientbundle"
 
-	filenameCAPem      = "ca.pem"
-	filenameCertPem    = "cert.pem"
-	filenamePrivKeyPem = "key.pem"
-	filenamePubKeyPem  = "cert.pub"
-	filenameKubeconfig = "kube.yml"
+	QueryKeyClientBundleLabel = "label"
 )
 
 var (
-	ErrFailedToRetrieveClientBundle         = errors.New("failed to retrieve the client bundle from MKE")
 	ErrFailedToFindClientBundleMKEPublicKey = errors.New("no MKE Public key was found that matches the client bundle")
 )
 
-// ApiClientBundle retrieve a client bundle(
-func (c *Client) ApiClientBundleCreate(ctx context.Context) (ClientBundle, error) {
+// ApiClientBundleCreate generate a new client bundle
+// The label is set on the bundle public key, so that it can be identified in the
+// MKE UI. If it is empty then MKE picks a label.
+func (c *Client) ApiClientBundleCreate(ctx context.Context, label string) (ClientBundle, error) {
 	var cb ClientBundle
 
 	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodPost, URLTargetForClientBundle, []byte{})
@@ -35,6 +30,12 @@ func (c *Client) ApiClientBundleCreate(ctx context.Context) (ClientBundle, error
 		return cb, err
 	}
 
+	if label != "" {
+		query := req.URL.Query()
+		query.Set(QueryKeyClientBundleLabel, label)
+		req.URL.RawQuery = query.Encode()
+	}
+
 	resp, err := c.doAuthorizedRequest(req)
 	if err != nil {
 		return cb, err
@@ -46,104 +47,51 @@ func (c *Client) ApiClientBundleCreate(ctx context.Context) (ClientBundle, error
 		return cb, err
 	}
 
-	zipReader, err := zip.NewReader(bytes.NewReader(zipBytes), resp.ContentLength)
-	if err != nil {
-		return cb, err
-	}
-
-	cb.ID = zipReader.Comment
-
-	errs := []error{}
-
-	for _, f := range zipReader.File {
-		switch f.Name {
-		case filenameCAPem:
-			fReader, _ := f.Open()
-			capem, err := ClientBundleRetrieveValue(fReader)
-			fReader.Close()
-
-			if err != nil {
-				errs = append(errs, err)
-			} else {
-				cb.CACert = capem
-			}
-		case filenameCertPem:
-			fReader, _ := f.Open()
-			cert, err := ClientBundleRetrieveValue(fReader)
-			fReader.Close()
-
-			if err != nil {
-				errs = append(errs, err)
-			} else {
-				cb.Cert = cert
-			}
-		case filenamePrivKeyPem:
-			fReader, _ := f.Open()
-			capem, err := ClientBundleRetrieveValue(fReader)
-			fReader.Close()
-
-			if err != nil {
-				errs = append(errs, err)
-			} else {
-				cb.PrivateKey = capem
-			}
-		case filenamePubKeyPem:
-			fReader, _ := f.Open()
-			capem, err := ClientBundleRetrieveValue(fReader)
-			fReader.Close()
-
-			if err != nil {
-				errs = append(errs, err)
-			} else {
-				cb.PublicKey = capem
-			}
-		case filenameKubeconfig:
-			fReader, _ := f.Open()
-			kube, err := NewClientBundleKubeFromKubeYml(fReader)
-			fReader.Close()
-
-			if err != nil {
-				errs = append(errs, err)
-			} else {
-				cb.Kube = &kube
-			}
-
-		}
-	}
-
-	if len(errs) > 0 {
-		errString := ""
-
-		for _, err := range errs {
-			errString = fmt.Sprintf("%s, %s", errString, err)
-		}
-
-		return cb, fmt.Errorf("%w; %s", ErrFailedToRetrieveClientBundle, errString)
-	}
+	cb, err = NewClientBundleFromZip(zipBytes)
+	cb.Label = label
+	return cb, err
+}
 
-	return cb, nil
+// ApiClientBundleCreateForAccount generate a new client bundle for another
+// account, such as a service account
+// MKE only generates bundles for the account that is logged in, so a separate
+// login is made with the account credentials.
+func (c *Client) ApiClientBundleCreateForAccount(ctx context.Context, auth Auth, label string) (ClientBundle, error) {
+	cb, err := c.WithAuth(&auth).ApiClientBundleCreate(ctx, label)
+	cb.Account = auth.Username
+	return cb, err
 }
 
 // ApiClientBundleGetPublicKey retrieve a client bundle by finding the matching public key
-// There isn't really a great way of doing this.
+// There isn't really a great way of doing this. If the bundle has a label then
+// only keys with that label are compared.
+// The account keys are walked through a page at a time, stopping at the first
+// page with a match.
 func (c *Client) ApiClientBundleGetPublicKey(ctx context.Context, cb ClientBundle) (AccountPublicKey, error) {
 	var k AccountPublicKey
 
-	account := c.Username()
-
-	keys, err := c.ApiPublicKeyList(ctx, account)
-	if err != nil {
-		return k, err
-	}
+	account := cb.accountOr(c.Username())
 
 	foundKeys := []string{}
 	cbpk := strings.TrimSpace(cb.PublicKey)
-	for _, key := range keys {
-		pk := strings.TrimSpace(key.PublicKey)
-		if pk == cbpk {
-			return key, nil
+
+	pager := c.NewPager(fmt.Sprintf(URLTargetPatternForPublicKeys, account), NewPageOptionsDefault())
+	for pager.More() {
+		var page GetKeysResponse
+		if err := pager.Next(ctx, &page); err != nil {
+			return k, err
+		}
+
+		for _, key := range page.AccountPubKeys {
+			if cb.Label != "" && key.Label != cb.Label {
+				continue
+			}
+			pk := strings.TrimSpace(key.PublicKey)
+			if pk == cbpk {
+				return key, nil
+			}
+			foundKeys = append(foundKeys, pk)
 		}
-		foundKeys = append(foundKeys, pk)
 	}
 
 	return k, fmt.Errorf("%w; Could not match key: \n%s\n in \n%s", ErrFailedToFindClientBundleMKEPublicKey, cb.PublicKey, strings.Join(foundKeys, "\n"))
@@ -151,8 +99,10 @@ func (c *Client) ApiClientBundleGetPublicKey(ctx context.Context, cb ClientBundl
 
 // ApiClientBundleDelete delete a client bundle by finding and deleting the matching public key
 // There isn't really a great way of doing this.
+// Bundles of other accounts can be deleted by an admin, without logging in as
+// that account.
 func (c *Client) ApiClientBundleDelete(ctx context.Context, cb ClientBundle) error {
-	account := c.Username()
+	account := cb.accountOr(c.Username())
 
 	key, err := c.ApiClientBundleGetPublicKey(ctx, cb)
 	if err != nil {
@@ -0,0 +1,213 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestClientBundleCreateWithLabel(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	cert, key := MockCertificate(auth.Username)
+	mockRequest := MockHandlerKey{
+		Path:   client.URLTargetForClientBundle,
+		Method: http.MethodPost,
+	}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		mockRequest: func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get(client.QueryKeyClientBundleLabel) != "mybundle" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write(MockClientBundleZip(map[string]string{
+				"cert.pem": cert,
+				"key.pem":  key,
+				"cert.pub": "mypublickey",
+			}))
+		},
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	cb, err := c.ApiClientBundleCreate(ctx, "mybundle")
+	if err != nil {
+		t.Fatalf("Failed to create client bundle: %s", err)
+	}
+	if cb.Label != "mybundle" || cb.PublicKey != "mypublickey" || cb.PrivateKey != key {
+		t.Errorf("Wrong client bundle created: %+v", cb)
+	}
+}
+
+func TestClientBundleGetPublicKeyByLabel(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	mockRequest := MockHandlerKey{
+		Path:   fmt.Sprintf(client.URLTargetPatternForPublicKeys, auth.Username),
+		Method: http.MethodGet,
+	}
+	keysResp := client.GetKeysResponse{
+		AccountPubKeys: []client.AccountPublicKey{
+			{ID: "OTHER", Label: "otherbundle", PublicKey: "mypublickey"},
+			{ID: "MINE", Label: "mybundle", PublicKey: "mypublickey\n"},
+		},
+	}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		mockRequest: MockServerHandlerGeneratorReturnJson(keysResp),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	key, err := c.ApiClientBundleGetPublicKey(ctx, client.ClientBundle{Label: "mybundle", PublicKey: "mypublickey"})
+	if err != nil {
+		t.Fatalf("Failed to find the client bundle public key: %s", err)
+	}
+	if key.ID != "MINE" {
+		t.Errorf("Found the wrong public key: %+v", key)
+	}
+
+	if _, err := c.ApiClientBundleGetPublicKey(ctx, client.ClientBundle{Label: "nobundle", PublicKey: "mypublickey"}); err == nil {
+		t.Error("Found a public key with the wrong label")
+	}
+}
+
+func TestClientBundleGetPublicKeyStopsAtMatch(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	mockRequest := MockHandlerKey{
+		Path:   fmt.Sprintf(client.URLTargetPatternForPublicKeys, auth.Username),
+		Method: http.MethodGet,
+	}
+	pages := 0
+
+	// the first page has the key, and says that there are more pages
+	svr := MockTestServer(&auth, MockHandlerMap{
+		mockRequest: func(w http.ResponseWriter, r *http.Request) {
+			pages++
+			if r.URL.Query().Get(client.QueryKeyPageStart) != "" {
+				MockServerHandlerGeneratorReturnJson(client.GetKeysResponse{})(w, r)
+				return
+			}
+			MockServerHandlerGeneratorReturnJson(client.GetKeysResponse{
+				AccountPubKeys: []client.AccountPublicKey{
+					{ID: "MINE", Label: "mybundle", PublicKey: "mypublickey"},
+				},
+				NextPageStart: "nextpage",
+			})(w, r)
+		},
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	key, err := c.ApiClientBundleGetPublicKey(ctx, client.ClientBundle{Label: "mybundle", PublicKey: "mypublickey"})
+	if err != nil {
+		t.Fatalf("Failed to find the client bundle public key: %s", err)
+	}
+	if key.ID != "MINE" {
+		t.Errorf("Found the wrong public key: %+v", key)
+	}
+	if pages != 1 {
+		t.Errorf("Expected the lookup to stop after the first page, %d pages were retrieved", pages)
+	}
+}
+
+func TestClientBundleCreateForAccount(t *testing.T) {
+	ctx := context.Background()
+	adminAuth := client.NewAuthToken("admintoken")
+	adminAuth.Username = "admin"
+	serviceAuth := client.Auth{
+		Username: "myservice",
+		Password: "myservicepassword",
+		Token:    "myservicetoken",
+	}
+	cert, key := MockCertificate(serviceAuth.Username)
+
+	// the server only knows the service account, so the bundle has to be
+	// created with its own login
+	svr := MockTestServer(&serviceAuth, MockHandlerMap{
+		MockHandlerKey{Path: client.URLTargetForClientBundle, Method: http.MethodPost}: MockServerHandlerGeneratorReturnBytes(MockClientBundleZip(map[string]string{
+			"cert.pem": cert,
+			"key.pem":  key,
+			"cert.pub": "mypublickey",
+		})),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &adminAuth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	cb, err := c.ApiClientBundleCreateForAccount(ctx, client.NewAuthUP(serviceAuth.Username, serviceAuth.Password), "mybundle")
+	if err != nil {
+		t.Fatalf("Failed to create client bundle for another account: %s", err)
+	}
+	if cb.Account != serviceAuth.Username || cb.PrivateKey != key {
+		t.Errorf("Wrong client bundle created: %+v", cb)
+	}
+	if cn, _ := cb.CertCommonName(); cn != serviceAuth.Username {
+		t.Errorf("Client bundle certificate is for the wrong account: %s", cn)
+	}
+	if c.Username() != adminAuth.Username {
+		t.Errorf("Creating a bundle for another account changed the client account: %s", c.Username())
+	}
+}
+
+func TestClientBundleDeleteForAccount(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "admin",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	keysResp := client.GetKeysResponse{
+		AccountPubKeys: []client.AccountPublicKey{
+			{ID: "SERVICEKEY", PublicKey: "mypublickey"},
+		},
+	}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForPublicKeys, "myservice"), Method: http.MethodGet}:                 MockServerHandlerGeneratorReturnJson(keysResp),
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForPublicKey, "myservice", "SERVICEKEY"), Method: http.MethodDelete}: MockServerHandlerGeneratorReturnResponseStatus(http.StatusNoContent),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	if err := c.ApiClientBundleDelete(ctx, client.ClientBundle{Account: "myservice", PublicKey: "mypublickey"}); err != nil {
+		t.Errorf("Failed to delete the client bundle of another account: %s", err)
+	}
+}
@@ -1,10 +1,21 @@
 package client_test
 
 import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"io/ioutil"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"time"
 	"unicode/utf8"
 
 	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
@@ -17,7 +28,8 @@ It will handle authentication responses, and any additional routes that you pass
 Routes are added as MockHandlers matching URLs.
 
 Authentication is handled as a passed in Auth struct. The .Username and .Password are
-verified and then the .Token is returned. If not authentication is to be done then
+verified and then the .Token is returned. If the Auth has a .TOTPSecret then a
+matching two factor code is also required. If not authentication is to be done then
 a nil Auth should be provided, and an error will occur if authentication is attempted.
 
 */
@@ -35,7 +47,12 @@ type MockHandler func(w http.ResponseWriter, r *http.Request)
 // Generate a test API server which will be usable for testing API Calls
 // if auth passed is nil, then no authentication occurs, otherwise U/P are expected for auth, and Token is returned
 func MockTestServer(auth *client.Auth, handlers MockHandlerMap) *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	return httptest.NewServer(MockTestHandler(auth, handlers))
+}
+
+// Generate the http.Handler used by MockTestServer, so that tests can wrap it
+func MockTestHandler(auth *client.Auth, handlers MockHandlerMap) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// strip the leading slash from the path
 		path := r.URL.Path
 		_, i := utf8.DecodeRuneInString(path)
@@ -63,6 +80,22 @@ func MockTestServer(auth *client.Auth, handlers MockHandlerMap) *httptest.Server
 					w.WriteHeader(http.StatusUnauthorized)
 				}
 
+				if auth.TOTPSecret != "" {
+					// accept the code for the current or the previous period
+					currentCode, _ := client.TOTPCode(auth.TOTPSecret, time.Now())
+					previousCode, _ := client.TOTPCode(auth.TOTPSecret, time.Now().Add(-client.TOTPPeriod))
+
+					if reqAuth.Code == "" {
+						w.WriteHeader(http.StatusUnauthorized)
+						w.Write([]byte(`{"errors":[{"code":"` + client.APIErrorCodeMFARequired + `","message":"two factor code required"}]}`))
+						return
+					} else if reqAuth.Code != currentCode && reqAuth.Code != previousCode {
+						w.WriteHeader(http.StatusUnauthorized)
+						w.Write([]byte(`{"errors":[{"code":"` + client.APIErrorCodeInvalidMFACode + `","message":"invalid two factor code"}]}`))
+						return
+					}
+				}
+
 				lr := client.NewLoginResponse(auth.Token)
 
 				w.Write(lr.Bytes())
@@ -97,7 +130,7 @@ func MockTestServer(auth *client.Auth, handlers MockHandlerMap) *httptest.Server
 			handler(w, r)
 		}
 
-	}))
+	})
 }
 
 // MockServerHandlerGeneratorReturnResponseStatus generates a MockHandler which just sets http status
@@ -121,3 +154,42 @@ func MockServerHandlerGeneratorReturnJson(expected interface{}) MockHandler {
 		w.Write(expectedBytes)
 	}
 }
+
+// MockCertificate generates a self signed certificate and key as PEM strings
+// The certificate can be used as a CA, a server certificate and a client certificate.
+func MockCertificate(commonName string) (string, string) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              []string{"localhost", "example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDER, _ := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return string(certPEM), string(keyPEM)
+}
+
+// MockClientBundleZip generates client bundle zip bytes from a map of file names to contents
+func MockClientBundleZip(files map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, contents := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(contents))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
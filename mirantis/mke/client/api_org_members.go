@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// /accounts/{orgNameOrID}/members
+	URLTargetPatternForOrgMembers = "accounts/%s/members"
+	// /accounts/{orgNameOrID}/members/{memberNameOrID}
+	URLTargetPatternForOrgMember = "accounts/%s/members/%s"
+)
+
+// ApiOrgMemberList list all of the members of an organization
+func (c *Client) ApiOrgMemberList(ctx context.Context, org string) ([]Member, error) {
+	u := fmt.Sprintf(URLTargetPatternForOrgMembers, org)
+
+	var members []Member
+
+	pager := c.NewPager(u, NewPageOptionsDefault())
+	for pager.More() {
+		var page GetMembersResponse
+		if err := pager.Next(ctx, &page); err != nil {
+			return members, err
+		}
+		members = append(members, page.Members...)
+	}
+
+	return members[:pager.Trim(len(members))], nil
+}
+
+// ApiOrgMemberRetrieve retrieve the membership of a user in an organization
+func (c *Client) ApiOrgMemberRetrieve(ctx context.Context, org, member string) (Member, error) {
+	u := fmt.Sprintf(URLTargetPatternForOrgMember, org, member)
+
+	var m Member
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodGet, u, []byte{})
+	if err != nil {
+		return m, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return m, err
+	}
+
+	if err = resp.JSONMarshallBody(&m); err != nil {
+		return m, err
+	}
+
+	return m, nil
+}
+
+// ApiOrgMemberAdd add a user to an organization, or change their membership,
+// such as making them an org admin
+func (c *Client) ApiOrgMemberAdd(ctx context.Context, org, member string, membership SetMembership) (Member, error) {
+	u := fmt.Sprintf(URLTargetPatternForOrgMember, org, member)
+
+	var m Member
+
+	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPut, u, membership)
+	if err != nil {
+		return m, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return m, err
+	}
+
+	if err = resp.JSONMarshallBody(&m); err != nil {
+		return m, err
+	}
+
+	return m, nil
+}
+
+// ApiOrgMemberDelete remove a user from an organization, and all of its teams
+func (c *Client) ApiOrgMemberDelete(ctx context.Context, org, member string) error {
+	u := fmt.Sprintf(URLTargetPatternForOrgMember, org, member)
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodDelete, u, []byte{})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doAuthorizedRequest(req)
+	return err
+}
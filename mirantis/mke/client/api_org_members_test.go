@@ -0,0 +1,53 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestOrgMemberAddAndRetrieve(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+
+	target := fmt.Sprintf(client.URLTargetPatternForOrgMember, "myorg", "alice")
+	svr := MockTestServer(&auth, MockHandlerMap{
+		MockHandlerKey{Path: target, Method: http.MethodPut}: func(w http.ResponseWriter, r *http.Request) {
+			var membership client.SetMembership
+			if err := json.NewDecoder(r.Body).Decode(&membership); err != nil || !membership.IsAdmin {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			MockServerHandlerGeneratorReturnJson(client.Member{Member: client.Account{Name: "alice"}, IsAdmin: true})(w, r)
+		},
+		MockHandlerKey{Path: target, Method: http.MethodGet}: MockServerHandlerGeneratorReturnJson(client.Member{Member: client.Account{Name: "alice"}, IsAdmin: true}),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	if member, err := c.ApiOrgMemberAdd(ctx, "myorg", "alice", client.SetMembership{IsAdmin: true}); err != nil || !member.IsAdmin {
+		t.Errorf("Add org member failed: %+v, %s", member, err)
+	}
+
+	if member, err := c.ApiOrgMemberRetrieve(ctx, "myorg", "alice"); err != nil || member.Member.Name != "alice" {
+		t.Errorf("Retrieve org member failed: %+v, %s", member, err)
+	}
+
+	if _, err := c.ApiOrgMemberRetrieve(ctx, "myorg", "bob"); !errors.Is(err, client.ErrUnknownTarget) {
+		t.Errorf("Wrong error retrieving a non member: %s", err)
+	}
+}
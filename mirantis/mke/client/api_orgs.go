@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+/**
+Organizations are accounts with isOrg set, so they use the account API, but
+are checked to be organizations when retrieved.
+*/
+
+var (
+	ErrAccountNotOrg = errors.New("MKE account is not an organization")
+)
+
+// ApiOrgList list all of the organizations
+func (c *Client) ApiOrgList(ctx context.Context) ([]Account, error) {
+	return c.ApiAccountList(ctx, AccountFilterOrgs)
+}
+
+// ApiOrgCreate create an organization
+func (c *Client) ApiOrgCreate(ctx context.Context, name, fullName string) (Account, error) {
+	return c.ApiAccountCreate(ctx, CreateAccount{
+		Name:     name,
+		FullName: fullName,
+		IsOrg:    true,
+	})
+}
+
+// ApiOrgRetrieve retrieve an organization by name or ID
+func (c *Client) ApiOrgRetrieve(ctx context.Context, org string) (Account, error) {
+	a, err := c.ApiAccountRetrieve(ctx, org)
+	if err != nil {
+		return a, err
+	}
+	if !a.IsOrg {
+		return a, fmt.Errorf("%w; %s", ErrAccountNotOrg, org)
+	}
+	return a, nil
+}
+
+// ApiOrgUpdate change the full name of an organization
+func (c *Client) ApiOrgUpdate(ctx context.Context, org, fullName string) (Account, error) {
+	return c.ApiAccountUpdate(ctx, org, UpdateAccount{FullName: &fullName})
+}
+
+// ApiOrgDelete delete an organization
+func (c *Client) ApiOrgDelete(ctx context.Context, org string) error {
+	return c.ApiAccountDelete(ctx, org)
+}
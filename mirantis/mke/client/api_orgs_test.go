@@ -0,0 +1,53 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestOrgCreateAndRetrieve(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		MockHandlerKey{Path: client.URLTargetForAccounts, Method: http.MethodPost}: func(w http.ResponseWriter, r *http.Request) {
+			var create client.CreateAccount
+			if err := json.NewDecoder(r.Body).Decode(&create); err != nil || !create.IsOrg || create.Password != "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			MockServerHandlerGeneratorReturnJson(client.Account{ID: "orgid", Name: create.Name, FullName: create.FullName, IsOrg: true})(w, r)
+		},
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForAccount, "myorg"), Method: http.MethodGet}:  MockServerHandlerGeneratorReturnJson(client.Account{ID: "orgid", Name: "myorg", IsOrg: true}),
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForAccount, "myuser"), Method: http.MethodGet}: MockServerHandlerGeneratorReturnJson(client.Account{ID: "userid", Name: "myuser"}),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	if org, err := c.ApiOrgCreate(ctx, "myorg", "My Org"); err != nil || org.ID != "orgid" || !org.IsOrg {
+		t.Errorf("Create org failed: %+v, %s", org, err)
+	}
+
+	if org, err := c.ApiOrgRetrieve(ctx, "myorg"); err != nil || org.ID != "orgid" {
+		t.Errorf("Retrieve org failed: %+v, %s", org, err)
+	}
+
+	if _, err := c.ApiOrgRetrieve(ctx, "myuser"); !errors.Is(err, client.ErrAccountNotOrg) {
+		t.Errorf("Wrong error retrieving a user as an org: %s", err)
+	}
+}
@@ -18,42 +18,67 @@ type GetKeysResponse struct {
 	NextPageStart  string             `json:"nextPageStart"`
 }
 
+// PageLength for the Page interface
+func (gkr GetKeysResponse) PageLength() int {
+	return len(gkr.AccountPubKeys)
+}
+
+// PageNextStart for the Page interface
+func (gkr GetKeysResponse) PageNextStart() string {
+	return gkr.NextPageStart
+}
+
 // ApiPublicKeyList list all of the public keys
 func (c *Client) ApiPublicKeyList(ctx context.Context, account string) ([]AccountPublicKey, error) {
 	u := fmt.Sprintf(URLTargetPatternForPublicKeys, account)
 
 	var keys []AccountPublicKey
 
-	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodGet, u, []byte{})
-	if err != nil {
-		return keys, err
-	}
-
-	for {
-		resp, err := c.doAuthorizedRequest(req)
-		if err != nil {
+	pager := c.NewPager(u, NewPageOptionsDefault())
+	for pager.More() {
+		var page GetKeysResponse
+		if err := pager.Next(ctx, &page); err != nil {
 			return keys, err
 		}
+		keys = append(keys, page.AccountPubKeys...)
+	}
 
-		var respContents GetKeysResponse
+	return keys[:pager.Trim(len(keys))], nil
+}
 
-		if err := resp.JSONMarshallBody(&respContents); err != nil {
-			return keys, err
-		}
+// ApiPublicKeyCreate register an existing public key for an account, with any
+// certificates for it
+// MKE returns the created key, with the certificate chain that it signed.
+func (c *Client) ApiPublicKeyCreate(ctx context.Context, account, publicKey, label string, certificates []Certificate) (AccountPublicKey, error) {
+	u := fmt.Sprintf(URLTargetPatternForPublicKeys, account)
 
-		keys = append(keys, respContents.AccountPubKeys...)
+	var k AccountPublicKey
 
-		if respContents.NextPageStart == "" {
-			break
-		}
+	create := struct {
+		PublicKey    string        `json:"publicKey"`
+		Label        string        `json:"label,omitempty"`
+		Certificates []Certificate `json:"certificates,omitempty"`
+	}{
+		PublicKey:    publicKey,
+		Label:        label,
+		Certificates: certificates,
+	}
+
+	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPost, u, create)
+	if err != nil {
+		return k, err
+	}
 
-		reqQuery := req.URL.Query()
-		reqQuery.Set("age", respContents.NextPageStart)
-		req.URL.RawQuery = reqQuery.Encode()
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return k, err
+	}
 
+	if err = resp.JSONMarshallBody(&k); err != nil {
+		return k, err
 	}
 
-	return keys, nil
+	return k, nil
 }
 
 // ApiPublicKeyRetrieve retrieve a specific account key
@@ -79,6 +104,33 @@ func (c *Client) ApiPublicKeyRetrieve(ctx context.Context, account, keyid string
 	return k, nil
 }
 
+// ApiPublicKeyUpdateLabel change the label of a specific account key
+func (c *Client) ApiPublicKeyUpdateLabel(ctx context.Context, account, keyid, label string) (AccountPublicKey, error) {
+	u := fmt.Sprintf(URLTargetPatternForPublicKey, account, keyid)
+
+	var k AccountPublicKey
+
+	update := struct {
+		Label string `json:"label"`
+	}{Label: label}
+
+	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPatch, u, update)
+	if err != nil {
+		return k, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return k, err
+	}
+
+	if err = resp.JSONMarshallBody(&k); err != nil {
+		return k, err
+	}
+
+	return k, nil
+}
+
 // ApiPublicKeyDelete delete a specific account key
 func (c *Client) ApiPublicKeyDelete(ctx context.Context, account, keyid string) error {
 	u := fmt.Sprintf(URLTargetPatternForPublicKey, account, keyid)
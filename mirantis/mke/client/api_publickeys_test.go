@@ -2,6 +2,7 @@ package client_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -50,6 +51,38 @@ func TestSimpleGetKeys(t *testing.T) {
 
 }
 
+func TestPagedGetKeys(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	mockRequest := MockHandlerKey{
+		Path:   fmt.Sprintf(client.URLTargetPatternForPublicKeys, auth.Username),
+		Method: http.MethodGet,
+	}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		mockRequest: mockHandlerPagedKeys(250),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	keys, err := c.ApiPublicKeyList(ctx, auth.Username)
+	if err != nil {
+		t.Fatalf("get keys request failed: %s", err)
+	}
+
+	if len(keys) != 250 {
+		t.Errorf("expected 250 keys across all pages, got %d", len(keys))
+	}
+}
+
 func TestSimpleDeleteKey(t *testing.T) {
 	ctx := context.Background()
 	auth := client.Auth{
@@ -78,3 +111,84 @@ func TestSimpleDeleteKey(t *testing.T) {
 	}
 
 }
+
+func TestUpdateKeyLabel(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	keyID := "ASDFASDF"
+	mockRequest := MockHandlerKey{
+		Path:   fmt.Sprintf(client.URLTargetPatternForPublicKey, auth.Username, keyID),
+		Method: http.MethodPatch,
+	}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		mockRequest: func(w http.ResponseWriter, r *http.Request) {
+			var update client.AccountPublicKey
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil || update.Label != "newlabel" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			MockServerHandlerGeneratorReturnJson(client.AccountPublicKey{ID: keyID, Label: update.Label})(w, r)
+		},
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	key, err := c.ApiPublicKeyUpdateLabel(ctx, auth.Username, keyID, "newlabel")
+	if err != nil {
+		t.Fatalf("Failed to update key label: %s", err)
+	}
+	if key.ID != keyID || key.Label != "newlabel" {
+		t.Errorf("Wrong key returned from label update: %+v", key)
+	}
+}
+
+func TestCreateKey(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	cert, _ := MockCertificate("myservice")
+	mockRequest := MockHandlerKey{
+		Path:   fmt.Sprintf(client.URLTargetPatternForPublicKeys, "myservice"),
+		Method: http.MethodPost,
+	}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		mockRequest: func(w http.ResponseWriter, r *http.Request) {
+			var create client.AccountPublicKey
+			if err := json.NewDecoder(r.Body).Decode(&create); err != nil || create.PublicKey != "mypublickey" || create.Label != "mykey" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			create.ID = "NEWKEY"
+			create.Certificates = []client.Certificate{{Label: "signed", Cert: cert}}
+			w.WriteHeader(http.StatusCreated)
+			MockServerHandlerGeneratorReturnJson(create)(w, r)
+		},
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	key, err := c.ApiPublicKeyCreate(ctx, "myservice", "mypublickey", "mykey", nil)
+	if err != nil {
+		t.Fatalf("Failed to create key: %s", err)
+	}
+	if key.ID != "NEWKEY" || len(key.Certificates) != 1 || key.Certificates[0].Cert != cert {
+		t.Errorf("Wrong key returned from create: %+v", key)
+	}
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// /roles
+	URLTargetForRoles = "roles"
+	// /roles/{roleNameOrID}
+	URLTargetPatternForRole = "roles/%s"
+)
+
+// ApiRoleList list all of the roles, including the MKE system roles
+func (c *Client) ApiRoleList(ctx context.Context) ([]Role, error) {
+	var roles []Role
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodGet, URLTargetForRoles, []byte{})
+	if err != nil {
+		return roles, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return roles, err
+	}
+
+	if err = resp.JSONMarshallBody(&roles); err != nil {
+		return roles, err
+	}
+
+	return roles, nil
+}
+
+// ApiRoleCreate create a custom role
+func (c *Client) ApiRoleCreate(ctx context.Context, name string, operations RoleOperations) (Role, error) {
+	var r Role
+
+	create := struct {
+		Name       string         `json:"name"`
+		Operations RoleOperations `json:"operations"`
+	}{
+		Name:       name,
+		Operations: operations,
+	}
+
+	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPost, URLTargetForRoles, create)
+	if err != nil {
+		return r, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return r, err
+	}
+
+	if err = resp.JSONMarshallBody(&r); err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
+
+// ApiRoleRetrieve retrieve a role by name or ID
+func (c *Client) ApiRoleRetrieve(ctx context.Context, role string) (Role, error) {
+	u := fmt.Sprintf(URLTargetPatternForRole, role)
+
+	var r Role
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodGet, u, []byte{})
+	if err != nil {
+		return r, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return r, err
+	}
+
+	if err = resp.JSONMarshallBody(&r); err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
+
+// ApiRoleDelete delete a custom role
+func (c *Client) ApiRoleDelete(ctx context.Context, role string) error {
+	u := fmt.Sprintf(URLTargetPatternForRole, role)
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodDelete, u, []byte{})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doAuthorizedRequest(req)
+	return err
+}
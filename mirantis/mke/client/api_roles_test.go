@@ -0,0 +1,55 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestRoleCreateAndList(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+
+	ops := client.RoleOperations{"Container": {"Container View": {}}}
+	role := client.Role{ID: "roleid", Name: "viewer", Operations: ops}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		MockHandlerKey{Path: client.URLTargetForRoles, Method: http.MethodPost}: func(w http.ResponseWriter, r *http.Request) {
+			var create client.Role
+			if err := json.NewDecoder(r.Body).Decode(&create); err != nil || create.Name != "viewer" || len(create.Operations["Container"]) != 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			MockServerHandlerGeneratorReturnJson(role)(w, r)
+		},
+		MockHandlerKey{Path: client.URLTargetForRoles, Method: http.MethodGet}:                              MockServerHandlerGeneratorReturnJson([]client.Role{{ID: "admin", Name: "Full Control", SystemRole: true}, role}),
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForRole, "roleid"), Method: http.MethodGet}: MockServerHandlerGeneratorReturnJson(role),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	if r, err := c.ApiRoleCreate(ctx, "viewer", ops); err != nil || r.ID != "roleid" {
+		t.Errorf("Create role failed: %+v, %s", r, err)
+	}
+
+	if roles, err := c.ApiRoleList(ctx); err != nil || len(roles) != 2 || !roles[0].SystemRole {
+		t.Errorf("List roles failed: %+v, %s", roles, err)
+	}
+
+	if r, err := c.ApiRoleRetrieve(ctx, "roleid"); err != nil || r.Operations.Names()[0] != "Container View" {
+		t.Errorf("Retrieve role failed: %+v, %s", r, err)
+	}
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// /accounts/{orgNameOrID}/teams
+	URLTargetPatternForTeams = "accounts/%s/teams"
+	// /accounts/{orgNameOrID}/teams/{teamNameOrID}
+	URLTargetPatternForTeam = "accounts/%s/teams/%s"
+	// /accounts/{orgNameOrID}/teams/{teamNameOrID}/members
+	URLTargetPatternForTeamMembers = "accounts/%s/teams/%s/members"
+	// /accounts/{orgNameOrID}/teams/{teamNameOrID}/members/{memberNameOrID}
+	URLTargetPatternForTeamMember = "accounts/%s/teams/%s/members/%s"
+)
+
+// ApiTeamList list all of the teams in an organization
+func (c *Client) ApiTeamList(ctx context.Context, org string) ([]Team, error) {
+	u := fmt.Sprintf(URLTargetPatternForTeams, org)
+
+	var teams []Team
+
+	pager := c.NewPager(u, NewPageOptionsDefault())
+	for pager.More() {
+		var page GetTeamsResponse
+		if err := pager.Next(ctx, &page); err != nil {
+			return teams, err
+		}
+		teams = append(teams, page.Teams...)
+	}
+
+	return teams[:pager.Trim(len(teams))], nil
+}
+
+// ApiTeamCreate create a team in an organization
+func (c *Client) ApiTeamCreate(ctx context.Context, org string, create CreateTeam) (Team, error) {
+	u := fmt.Sprintf(URLTargetPatternForTeams, org)
+
+	var t Team
+
+	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPost, u, create)
+	if err != nil {
+		return t, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return t, err
+	}
+
+	if err = resp.JSONMarshallBody(&t); err != nil {
+		return t, err
+	}
+
+	return t, nil
+}
+
+// ApiTeamRetrieve retrieve a team by name or ID
+func (c *Client) ApiTeamRetrieve(ctx context.Context, org, team string) (Team, error) {
+	u := fmt.Sprintf(URLTargetPatternForTeam, org, team)
+
+	var t Team
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodGet, u, []byte{})
+	if err != nil {
+		return t, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return t, err
+	}
+
+	if err = resp.JSONMarshallBody(&t); err != nil {
+		return t, err
+	}
+
+	return t, nil
+}
+
+// ApiTeamUpdate change the details of a team
+func (c *Client) ApiTeamUpdate(ctx context.Context, org, team string, update UpdateTeam) (Team, error) {
+	u := fmt.Sprintf(URLTargetPatternForTeam, org, team)
+
+	var t Team
+
+	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPatch, u, update)
+	if err != nil {
+		return t, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return t, err
+	}
+
+	if err = resp.JSONMarshallBody(&t); err != nil {
+		return t, err
+	}
+
+	return t, nil
+}
+
+// ApiTeamDelete delete a team
+func (c *Client) ApiTeamDelete(ctx context.Context, org, team string) error {
+	u := fmt.Sprintf(URLTargetPatternForTeam, org, team)
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodDelete, u, []byte{})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doAuthorizedRequest(req)
+	return err
+}
+
+// ApiTeamMemberList list all of the members of a team
+func (c *Client) ApiTeamMemberList(ctx context.Context, org, team string) ([]Member, error) {
+	u := fmt.Sprintf(URLTargetPatternForTeamMembers, org, team)
+
+	var members []Member
+
+	pager := c.NewPager(u, NewPageOptionsDefault())
+	for pager.More() {
+		var page GetMembersResponse
+		if err := pager.Next(ctx, &page); err != nil {
+			return members, err
+		}
+		members = append(members, page.Members...)
+	}
+
+	return members[:pager.Trim(len(members))], nil
+}
+
+// ApiTeamMemberRetrieve retrieve the membership of a user in a team
+func (c *Client) ApiTeamMemberRetrieve(ctx context.Context, org, team, member string) (Member, error) {
+	u := fmt.Sprintf(URLTargetPatternForTeamMember, org, team, member)
+
+	var m Member
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodGet, u, []byte{})
+	if err != nil {
+		return m, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return m, err
+	}
+
+	if err = resp.JSONMarshallBody(&m); err != nil {
+		return m, err
+	}
+
+	return m, nil
+}
+
+// ApiTeamMemberAdd add a user to a team, or change their membership
+// The user must already be a member of the organization.
+func (c *Client) ApiTeamMemberAdd(ctx context.Context, org, team, member string, membership SetMembership) (Member, error) {
+	u := fmt.Sprintf(URLTargetPatternForTeamMember, org, team, member)
+
+	var m Member
+
+	req, err := c.RequestFromTargetAndJSONBody(ctx, http.MethodPut, u, membership)
+	if err != nil {
+		return m, err
+	}
+
+	resp, err := c.doAuthorizedRequest(req)
+	if err != nil {
+		return m, err
+	}
+
+	if err = resp.JSONMarshallBody(&m); err != nil {
+		return m, err
+	}
+
+	return m, nil
+}
+
+// ApiTeamMemberDelete remove a user from a team
+func (c *Client) ApiTeamMemberDelete(ctx context.Context, org, team, member string) error {
+	u := fmt.Sprintf(URLTargetPatternForTeamMember, org, team, member)
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, http.MethodDelete, u, []byte{})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doAuthorizedRequest(req)
+	return err
+}
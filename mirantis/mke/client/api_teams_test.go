@@ -0,0 +1,105 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestTeamCreateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForTeams, "myorg"), Method: http.MethodPost}: func(w http.ResponseWriter, r *http.Request) {
+			var create client.CreateTeam
+			if err := json.NewDecoder(r.Body).Decode(&create); err != nil || create.Name == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			MockServerHandlerGeneratorReturnJson(client.Team{ID: "teamid", OrgID: "orgid", Name: create.Name, Description: create.Description})(w, r)
+		},
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForTeam, "myorg", "myteam"), Method: http.MethodPatch}: func(w http.ResponseWriter, r *http.Request) {
+			var update client.UpdateTeam
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil || update.Description == nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			MockServerHandlerGeneratorReturnJson(client.Team{ID: "teamid", OrgID: "orgid", Name: "myteam", Description: *update.Description})(w, r)
+		},
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	if team, err := c.ApiTeamCreate(ctx, "myorg", client.CreateTeam{Name: "myteam", Description: "first"}); err != nil || team.ID != "teamid" || team.Description != "first" {
+		t.Errorf("Create team failed: %+v, %s", team, err)
+	}
+
+	description := "second"
+	if team, err := c.ApiTeamUpdate(ctx, "myorg", "myteam", client.UpdateTeam{Description: &description}); err != nil || team.Description != "second" {
+		t.Errorf("Update team failed: %+v, %s", team, err)
+	}
+}
+
+func TestTeamMemberListPaged(t *testing.T) {
+	ctx := context.Background()
+	auth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+
+	svr := MockTestServer(&auth, MockHandlerMap{
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForTeamMembers, "myorg", "myteam"), Method: http.MethodGet}: func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get(client.QueryKeyPageStart) == "" {
+				MockServerHandlerGeneratorReturnJson(client.GetMembersResponse{
+					Members:       []client.Member{{Member: client.Account{Name: "alice"}}},
+					NextPageStart: "bob",
+				})(w, r)
+				return
+			}
+			MockServerHandlerGeneratorReturnJson(client.GetMembersResponse{
+				Members: []client.Member{{Member: client.Account{Name: "bob"}, IsAdmin: true}},
+			})(w, r)
+		},
+		MockHandlerKey{Path: fmt.Sprintf(client.URLTargetPatternForTeamMember, "myorg", "myteam", "carol"), Method: http.MethodPut}: func(w http.ResponseWriter, r *http.Request) {
+			var membership client.SetMembership
+			if err := json.NewDecoder(r.Body).Decode(&membership); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			MockServerHandlerGeneratorReturnJson(client.Member{Member: client.Account{Name: "carol"}, IsAdmin: membership.IsAdmin})(w, r)
+		},
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	members, err := c.ApiTeamMemberList(ctx, "myorg", "myteam")
+	if err != nil {
+		t.Fatalf("List team members failed: %s", err)
+	}
+	if len(members) != 2 || members[0].Member.Name != "alice" || members[1].Member.Name != "bob" || !members[1].IsAdmin {
+		t.Errorf("Wrong team members listed: %+v", members)
+	}
+
+	if member, err := c.ApiTeamMemberAdd(ctx, "myorg", "myteam", "carol", client.SetMembership{IsAdmin: true}); err != nil || member.Member.Name != "carol" || !member.IsAdmin {
+		t.Errorf("Add team member failed: %+v, %s", member, err)
+	}
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/**
+
+# MKE API errors
+
+MKE (and eNZi) report failures using a JSON envelope:
+
+	{"errors": [{"code": "NO_SUCH_ACCOUNT", "message": "...", "detail": {...}}]}
+
+Responses with an error status are converted to an APIError, which keeps the
+parsed envelope along with the http status and the request that failed.
+An APIError matches the generic ErrResponseError sentinel using errors.Is, as
+well as a sentinel for the status where we have one, such as ErrUnknownTarget
+for a 404.
+*/
+
+var (
+	ErrConflict = errors.New("conflict with existing MKE resource")
+)
+
+// APIErrorDetail a single error from the MKE error envelope
+type APIErrorDetail struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Detail  interface{} `json:"detail,omitempty"`
+}
+
+// APIError an error response from the MKE API
+type APIError struct {
+	StatusCode int
+	Method     string
+	Target     string
+	Errors     []APIErrorDetail
+	// Body raw response body, kept in case it was not a JSON envelope
+	Body []byte
+
+	sentinel error
+}
+
+// NewAPIError APIError constructor from an http response and its body
+func NewAPIError(res *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: res.StatusCode,
+		Body:       body,
+		sentinel:   sentinelForStatus(res.StatusCode),
+	}
+
+	if res.Request != nil {
+		e.Method = res.Request.Method
+		if res.Request.URL != nil {
+			e.Target = res.Request.URL.Path
+		}
+	}
+
+	var envelope struct {
+		Errors []APIErrorDetail `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil {
+		e.Errors = envelope.Errors
+	}
+
+	return e
+}
+
+// Error message including the MKE error codes and messages
+func (e *APIError) Error() string {
+	var msg string
+	if len(e.Errors) > 0 {
+		msgs := []string{}
+		for _, detail := range e.Errors {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", detail.Code, detail.Message))
+		}
+		msg = strings.Join(msgs, "; ")
+	} else {
+		msg = string(e.Body)
+	}
+
+	return fmt.Sprintf("%s: %s %s: Status code: %d : %s", e.sentinel, e.Method, e.Target, e.StatusCode, msg)
+}
+
+// Unwrap the sentinel error for the response status
+func (e *APIError) Unwrap() error {
+	return e.sentinel
+}
+
+// Is every APIError a ErrResponseError, as well as its status sentinel
+func (e *APIError) Is(target error) bool {
+	return target == ErrResponseError
+}
+
+// HasCode did MKE report an error with the code
+func (e *APIError) HasCode(code string) bool {
+	for _, detail := range e.Errors {
+		if detail.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// sentinelForStatus which of our error sentinels an error status matches
+func sentinelForStatus(status int) error {
+	switch status {
+	case http.StatusUnauthorized:
+		return ErrUnauthorizedReq
+	case http.StatusNotFound:
+		return ErrUnknownTarget
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusInternalServerError:
+		return ErrServerError
+	}
+	return ErrResponseError
+}
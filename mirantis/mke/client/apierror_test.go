@@ -0,0 +1,104 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestAPIErrorEnvelope(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodPost,
+	}
+
+	svr := MockTestServer(nil, MockHandlerMap{
+		mockRequest: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"errors":[{"code":"ACCOUNT_EXISTS","message":"an account with this name already exists","detail":{"name":"myuser"}}]}`))
+		},
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, nil, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte{})
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	_, err = c.ApiGeneric(ctx, req)
+	if err == nil {
+		t.Fatal("Conflict response did not produce an error")
+	}
+
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Error was not an APIError: %s", err)
+	}
+	if apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("APIError had the wrong status: %d", apiErr.StatusCode)
+	}
+	if apiErr.Method != http.MethodPost || apiErr.Target != "/mypath" {
+		t.Errorf("APIError had the wrong request: %s %s", apiErr.Method, apiErr.Target)
+	}
+	if !apiErr.HasCode("ACCOUNT_EXISTS") {
+		t.Errorf("APIError did not parse the error code: %+v", apiErr.Errors)
+	}
+	if !errors.Is(err, client.ErrConflict) {
+		t.Error("APIError did not match the conflict sentinel")
+	}
+	if !errors.Is(err, client.ErrResponseError) {
+		t.Error("APIError did not match the response error sentinel")
+	}
+	if errors.Is(err, client.ErrUnknownTarget) {
+		t.Error("APIError matched the wrong sentinel")
+	}
+}
+
+func TestAPIErrorNotJSON(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodGet,
+	}
+
+	svr := MockTestServer(nil, MockHandlerMap{
+		mockRequest: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("page not found"))
+		},
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, nil, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte{})
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	_, err = c.ApiGeneric(ctx, req)
+
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Error was not an APIError: %s", err)
+	}
+	if len(apiErr.Errors) != 0 || string(apiErr.Body) != "page not found" {
+		t.Errorf("APIError did not keep the raw body: %+v", apiErr)
+	}
+	if !errors.Is(err, client.ErrUnknownTarget) {
+		t.Error("APIError did not match the not found sentinel")
+	}
+}
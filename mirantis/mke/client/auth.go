@@ -2,7 +2,11 @@ package client
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 )
 
 /**
@@ -11,14 +15,65 @@ import (
 
 MKE implements authentication using a bearer token that can be generated using
 a username/password login to an authentication API target.
-It is unclear how long this token lasts.S
+Alternatively MKE accepts requests over mutual TLS using the certificate from a
+client bundle, in which case no token is needed (AuthModeClientCert.)
+A token retrieved elsewhere can also be used directly (AuthModeToken), either
+as a value or from a file which is re-read whenever it changes. Such a token
+cannot be renewed by the client, so an expired token produces ErrTokenExpired.
 
-@TODO we should probably do more intelligent caching/renewal of tokens. We don't
-	have enough awareness to know how to tune that yet.
+MKE session tokens expire after a configurable session lifetime (60 minutes by
+default.) The client tracks when it retrieved a token and renews it according
+to a TokenPolicy before it expires. If MKE still rejects a token (e.g. the
+lifetime was configured shorter, or the session was revoked) then an
+authorized request is replayed once after a fresh login.
 */
 
 const (
 	HeaderKeyAuthorization = "Authorization"
+
+	// DefaultTokenLifetime MKE default session lifetime
+	DefaultTokenLifetime = 60 * time.Minute
+	// DefaultTokenRenewBefore how long before expiry a token is renewed by default
+	DefaultTokenRenewBefore = 5 * time.Minute
+)
+
+// TokenPolicy how long a login token is trusted before the client renews it
+type TokenPolicy struct {
+	// Lifetime how long MKE considers a token valid, 0 means never renew proactively
+	Lifetime time.Duration
+	// RenewBefore how long before the end of the lifetime the token is renewed
+	RenewBefore time.Duration
+}
+
+// NewTokenPolicyDefault TokenPolicy constructor matching MKE default session settings
+func NewTokenPolicyDefault() TokenPolicy {
+	return TokenPolicy{
+		Lifetime:    DefaultTokenLifetime,
+		RenewBefore: DefaultTokenRenewBefore,
+	}
+}
+
+// NeedsRenewal should a token retrieved at the issued time be renewed now
+// a zero issued time means that the token age is unknown (it was provided to
+// the client) in which case we rely on MKE to tell us that it expired.
+func (tp TokenPolicy) NeedsRenewal(issued time.Time) bool {
+	if tp.Lifetime <= 0 || issued.IsZero() {
+		return false
+	}
+	return time.Since(issued) >= tp.Lifetime-tp.RenewBefore
+}
+
+// AuthMode how the client authenticates its requests
+type AuthMode int
+
+const (
+	// AuthModePassword log in with username and password to retrieve a token
+	AuthModePassword AuthMode = iota
+	// AuthModeClientCert authenticate using the client certificate of the
+	// http client TLS config (e.g. from a client bundle), without logging in
+	AuthModeClientCert
+	// AuthModeToken use a token issued elsewhere, from Token or TokenFile
+	AuthModeToken
 )
 
 // Auth container for data related to authentication
@@ -29,6 +84,12 @@ type Auth struct {
 	Token    string `json:"token"`
 	UseTLS   bool   `json:"useTLS"`
 	Username string `json:"username"`
+
+	Mode AuthMode `json:"-"`
+	// TokenFile path to a file containing the token, for AuthModeToken
+	TokenFile string `json:"-"`
+	// TOTPSecret base32 secret used to generate a two factor Code for each login
+	TOTPSecret string `json:"-"`
 }
 
 // NewAuthSimple constructor for Auth from username and password
@@ -39,21 +100,105 @@ func NewAuthUP(username, password string) Auth {
 	}
 }
 
+// NewAuthClientCert constructor for Auth that relies on a TLS client certificate
+// the username is the account that the certificate belongs to
+func NewAuthClientCert(username string) Auth {
+	return Auth{
+		Username: username,
+		Mode:     AuthModeClientCert,
+	}
+}
+
+// NewAuthToken constructor for Auth that uses a pre-issued token
+func NewAuthToken(token string) Auth {
+	return Auth{
+		Token: token,
+		Mode:  AuthModeToken,
+	}
+}
+
+// NewAuthTokenFile constructor for Auth that reads a pre-issued token from a file
+func NewAuthTokenFile(path string) Auth {
+	return Auth{
+		TokenFile: path,
+		Mode:      AuthModeToken,
+	}
+}
+
+// canLogin can the auth retrieve a new token by logging in
+func (a *Auth) canLogin() bool {
+	return a.Mode == AuthModePassword
+}
+
 // authorizeRequest adds a token header to a request to authenticate it
-// this will retrieve a request if none has been retrieved.
-// this does not validate the token in any way (yet)
+// this will retrieve a token if none has been retrieved, or if the token
+// policy says that the current token is too old.
 func (c *Client) authorizeRequest(req *http.Request) error {
-	if c.auth.Token == "" {
-		if err := c.ApiLogin(req.Context()); err != nil {
+	if c.auth.Mode == AuthModeClientCert {
+		// the TLS handshake authenticates the request
+		return nil
+	}
+
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.auth.Mode == AuthModeToken {
+		if err := c.readTokenFile(); err != nil {
+			return err
+		}
+	} else if c.auth.Token == "" || c.TokenPolicy.NeedsRenewal(c.tokenIssued) {
+		if err := c.login(req.Context()); err != nil {
 			return err
 		}
 	}
 
-	req.Header.Add(HeaderKeyAuthorization, BearerTokenHeaderValue(c.auth.Token))
+	req.Header.Set(HeaderKeyAuthorization, BearerTokenHeaderValue(c.auth.Token))
 
 	return nil
 }
 
+// readTokenFile (re)load the token from the auth token file if it has changed
+// since it was last read. The caller must hold the token lock.
+func (c *Client) readTokenFile() error {
+	if c.auth.TokenFile == "" {
+		return nil
+	}
+
+	info, err := os.Stat(c.auth.TokenFile)
+	if err != nil {
+		return fmt.Errorf("%w; %s", ErrTokenFile, err)
+	}
+	if c.auth.Token != "" && info.ModTime().Equal(c.tokenFileModTime) {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(c.auth.TokenFile)
+	if err != nil {
+		return fmt.Errorf("%w; %s", ErrTokenFile, err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return fmt.Errorf("%w; %s is empty", ErrTokenFile, c.auth.TokenFile)
+	}
+
+	c.auth.Token = token
+	c.tokenFileModTime = info.ModTime()
+
+	return nil
+}
+
+// invalidateToken forget the token used to authorize a request, so that the next
+// request logs in again.
+// If another request has already replaced the token then it is kept.
+func (c *Client) invalidateToken(req *http.Request) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if req.Header.Get(HeaderKeyAuthorization) == BearerTokenHeaderValue(c.auth.Token) {
+		c.auth.Token = ""
+	}
+}
+
 // BearerTokenHeaderValue convert an auth token into the auth header value
 func BearerTokenHeaderValue(token string) string {
 	return fmt.Sprintf("Bearer %s", token)
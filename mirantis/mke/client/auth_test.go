@@ -2,11 +2,20 @@ package client_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
 )
@@ -93,3 +102,290 @@ func TestBearerTokenHeaderStringGenerate(t *testing.T) {
 		t.Error("Bearer header token build fail")
 	}
 }
+
+func TestExpiredTokenRequestReplayed(t *testing.T) {
+	ctx := context.Background()
+	srvAuth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	clAuth := client.Auth{
+		Username: srvAuth.Username,
+		Password: srvAuth.Password,
+		Token:    "myexpiredtoken",
+	}
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodPost,
+	}
+	expectedReqBody := "myrequest"
+	calls := 0
+
+	svr := MockTestServer(&srvAuth, MockHandlerMap{
+		mockRequest: func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if b, _ := ioutil.ReadAll(r.Body); string(b) != expectedReqBody {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		},
+	})
+
+	url, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(url, &clAuth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte(expectedReqBody))
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	if _, err := c.ApiAuthorizedGeneric(ctx, req); err != nil {
+		t.Errorf("Request with an expired token was not replayed: %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the request to be sent twice, it was sent %d times", calls)
+	}
+	if clAuth.Token != srvAuth.Token {
+		t.Errorf("Replay did not renew the token: %s != %s", clAuth.Token, srvAuth.Token)
+	}
+}
+
+func TestTokenPolicyNeedsRenewal(t *testing.T) {
+	tp := client.TokenPolicy{
+		Lifetime:    time.Hour,
+		RenewBefore: 5 * time.Minute,
+	}
+
+	if tp.NeedsRenewal(time.Time{}) {
+		t.Error("Token of unknown age should not be renewed")
+	}
+	if tp.NeedsRenewal(time.Now().Add(-time.Minute)) {
+		t.Error("Fresh token should not be renewed")
+	}
+	if !tp.NeedsRenewal(time.Now().Add(-56 * time.Minute)) {
+		t.Error("Token inside the renewal window should be renewed")
+	}
+	if (client.TokenPolicy{}).NeedsRenewal(time.Now().Add(-24 * time.Hour)) {
+		t.Error("Token policy without a lifetime should never renew")
+	}
+}
+
+func TestConcurrentRequestsLoginOnce(t *testing.T) {
+	ctx := context.Background()
+	srvAuth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	clAuth := client.Auth{
+		Username: srvAuth.Username,
+		Password: srvAuth.Password,
+	}
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodGet,
+	}
+	var logins int32
+
+	handler := MockTestHandler(&srvAuth, MockHandlerMap{
+		mockRequest: MockServerHandlerGeneratorReturnBytes([]byte("myresponse")),
+	})
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, client.URLTargetForAuth) {
+			atomic.AddInt32(&logins, 1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+
+	url, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(url, &clAuth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte{})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if _, err := c.ApiAuthorizedGeneric(ctx, req); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Concurrent authorized request failed: %s", err)
+	}
+	if logins := atomic.LoadInt32(&logins); logins != 1 {
+		t.Errorf("Concurrent requests should share a single login, got %d logins", logins)
+	}
+}
+
+func TestClientCertAuthorizedRequest(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodGet,
+	}
+	cert, key := MockCertificate("myuser")
+	cb := client.ClientBundle{
+		CACert:     cert,
+		Cert:       cert,
+		PrivateKey: key,
+	}
+
+	// no server auth, so any login attempt or Authorization header is rejected
+	svr := httptest.NewUnstartedServer(MockTestHandler(nil, MockHandlerMap{
+		mockRequest: MockServerHandlerGeneratorReturnBytes([]byte("myresponse")),
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM([]byte(cert))
+	serverCert, _ := tls.X509KeyPair([]byte(cert), []byte(key))
+	svr.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	svr.StartTLS()
+	defer svr.Close()
+
+	tlsConfig, err := cb.TLSOptions().TLSConfig()
+	if err != nil {
+		t.Fatalf("Could not build TLS config from the bundle: %s", err)
+	}
+
+	auth := client.NewAuthClientCert("myuser")
+	url, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(url, &auth, &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}})
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte{})
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	if _, err := c.ApiAuthorizedGeneric(ctx, req); err != nil {
+		t.Errorf("Client certificate authorized request failed: %s", err)
+	}
+	if err := c.ApiLogin(ctx); !errors.Is(err, client.ErrCannotLogin) {
+		t.Errorf("Client certificate auth should not log in: %s", err)
+	}
+}
+
+func TestTokenAuthorizedRequest(t *testing.T) {
+	ctx := context.Background()
+	srvAuth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodGet,
+	}
+
+	svr := MockTestServer(&srvAuth, MockHandlerMap{
+		mockRequest: MockServerHandlerGeneratorReturnBytes([]byte("myresponse")),
+	})
+
+	url, _ := url.Parse(svr.URL)
+
+	clAuth := client.NewAuthToken(srvAuth.Token)
+	c, err := client.NewClient(url, &clAuth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte{})
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	if _, err := c.ApiAuthorizedGeneric(ctx, req); err != nil {
+		t.Errorf("Token authorized request failed: %s", err)
+	}
+
+	expiredAuth := client.NewAuthToken("myexpiredtoken")
+	c, err = client.NewClient(url, &expiredAuth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	req, err = c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte{})
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	if _, err := c.ApiAuthorizedGeneric(ctx, req); !errors.Is(err, client.ErrTokenExpired) {
+		t.Errorf("Expired token did not produce the expected error: %s", err)
+	}
+}
+
+func TestTokenFileAuthorizedRequest(t *testing.T) {
+	ctx := context.Background()
+	srvAuth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodGet,
+	}
+
+	svr := MockTestServer(&srvAuth, MockHandlerMap{
+		mockRequest: MockServerHandlerGeneratorReturnBytes([]byte("myresponse")),
+	})
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	ioutil.WriteFile(tokenFile, []byte("myexpiredtoken\n"), 0600)
+
+	url, _ := url.Parse(svr.URL)
+	clAuth := client.NewAuthTokenFile(tokenFile)
+	c, err := client.NewClient(url, &clAuth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte{})
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	if _, err := c.ApiAuthorizedGeneric(ctx, req); !errors.Is(err, client.ErrTokenExpired) {
+		t.Errorf("Expired token did not produce the expected error: %s", err)
+	}
+
+	// a sidecar replaces the token
+	ioutil.WriteFile(tokenFile, []byte("mytoken\n"), 0600)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(tokenFile, later, later)
+
+	req, err = c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte{})
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	if _, err := c.ApiAuthorizedGeneric(ctx, req); err != nil {
+		t.Errorf("Token file was not re-read after it changed: %s", err)
+	}
+
+	os.Remove(tokenFile)
+	if _, err := c.ApiAuthorizedGeneric(ctx, req); !errors.Is(err, client.ErrTokenFile) {
+		t.Errorf("Missing token file did not produce the expected error: %s", err)
+	}
+}
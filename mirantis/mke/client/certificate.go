@@ -0,0 +1,92 @@
+package client
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidCertificate = errors.New("could not parse the certificate")
+)
+
+// CertificateInfo metadata of an x509 certificate, such as a client bundle cert
+type CertificateInfo struct {
+	Subject      string `json:"subject"`
+	CommonName   string `json:"common_name"`
+	Issuer       string `json:"issuer"`
+	SerialNumber string `json:"serial_number"`
+	// subject alternative names
+	DNSNames       []string `json:"dns_names"`
+	IPAddresses    []string `json:"ip_addresses"`
+	EmailAddresses []string `json:"email_addresses"`
+	URIs           []string `json:"uris"`
+
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	// FingerprintSHA256 lower case hex SHA256 hash of the DER certificate
+	FingerprintSHA256 string `json:"fingerprint_sha256"`
+}
+
+// NewCertificateInfoFromPEM CertificateInfo constructor from the first
+// certificate in a PEM string
+func NewCertificateInfoFromPEM(certPEM string) (CertificateInfo, error) {
+	rest := []byte(certPEM)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return CertificateInfo{}, fmt.Errorf("%w; no PEM encoded certificate found", ErrInvalidCertificate)
+		}
+		if block.Type == "CERTIFICATE" {
+			return NewCertificateInfoFromDER(block.Bytes)
+		}
+	}
+}
+
+// NewCertificateInfoFromDER CertificateInfo constructor from DER certificate bytes
+func NewCertificateInfoFromDER(certDER []byte) (CertificateInfo, error) {
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return CertificateInfo{}, fmt.Errorf("%w; %s", ErrInvalidCertificate, err)
+	}
+
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	ci := CertificateInfo{
+		Subject:           cert.Subject.String(),
+		CommonName:        cert.Subject.CommonName,
+		Issuer:            cert.Issuer.String(),
+		SerialNumber:      cert.SerialNumber.String(),
+		DNSNames:          cert.DNSNames,
+		IPAddresses:       []string{},
+		EmailAddresses:    cert.EmailAddresses,
+		URIs:              []string{},
+		NotBefore:         cert.NotBefore,
+		NotAfter:          cert.NotAfter,
+		FingerprintSHA256: hex.EncodeToString(fingerprint[:]),
+	}
+	for _, ip := range cert.IPAddresses {
+		ci.IPAddresses = append(ci.IPAddresses, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		ci.URIs = append(ci.URIs, uri.String())
+	}
+
+	return ci, nil
+}
+
+// ValidAt is the certificate inside its validity period at a time
+func (ci CertificateInfo) ValidAt(at time.Time) bool {
+	return !at.Before(ci.NotBefore) && !at.After(ci.NotAfter)
+}
+
+// ExpiresIn how long the certificate is valid for after a time, negative if it
+// has already expired
+func (ci CertificateInfo) ExpiresIn(at time.Time) time.Duration {
+	return ci.NotAfter.Sub(at)
+}
@@ -0,0 +1,51 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestCertificateInfoFromPEM(t *testing.T) {
+	cert, key := MockCertificate("myuser")
+
+	// the key comes first in some bundles, it should be skipped
+	ci, err := client.NewCertificateInfoFromPEM(key + cert)
+	if err != nil {
+		t.Fatalf("Error parsing certificate: %s", err)
+	}
+
+	if ci.CommonName != "myuser" || ci.Subject != "CN=myuser" || ci.Issuer != "CN=myuser" {
+		t.Errorf("Certificate has the wrong subject or issuer: %+v", ci)
+	}
+	if ci.SerialNumber != "1" {
+		t.Errorf("Certificate has the wrong serial number: %s", ci.SerialNumber)
+	}
+	if len(ci.DNSNames) != 2 || ci.DNSNames[0] != "localhost" || len(ci.IPAddresses) != 1 || ci.IPAddresses[0] != "127.0.0.1" {
+		t.Errorf("Certificate has the wrong SANs: %+v", ci)
+	}
+	if len(ci.FingerprintSHA256) != 64 {
+		t.Errorf("Certificate has a bad fingerprint: %s", ci.FingerprintSHA256)
+	}
+
+	now := time.Now()
+	if !ci.ValidAt(now) || ci.ValidAt(now.Add(48*time.Hour)) {
+		t.Errorf("Certificate has the wrong validity: %s - %s", ci.NotBefore, ci.NotAfter)
+	}
+	if in := ci.ExpiresIn(now); in <= 23*time.Hour || in > 24*time.Hour {
+		t.Errorf("Certificate expires at the wrong time: %s", in)
+	}
+}
+
+func TestCertificateInfoFromPEMInvalid(t *testing.T) {
+	if _, err := client.NewCertificateInfoFromPEM("not a certificate"); !errors.Is(err, client.ErrInvalidCertificate) {
+		t.Errorf("Wrong error for an invalid certificate: %s", err)
+	}
+
+	cb := client.ClientBundle{Cert: "-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n"}
+	if _, err := cb.CertificateInfo(); !errors.Is(err, client.ErrFailedToReadClientBundle) {
+		t.Errorf("Wrong error for an invalid bundle certificate: %s", err)
+	}
+}
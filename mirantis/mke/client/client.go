@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 )
 
 const (
@@ -17,27 +19,36 @@ var (
 )
 
 // Client MSR client
+// A Client is safe for concurrent use, and should be shared as a pointer so
+// that all users share the same auth token.
 type Client struct {
-	apiURL     *url.URL
-	auth       *Auth
-	HTTPClient *http.Client
+	apiURL *url.URL
+	auth   *Auth
+	// tokenMu guards the auth token and its timestamps, and is held during
+	// login so that concurrent requests wait for a single login.
+	tokenMu          sync.Mutex
+	tokenIssued      time.Time
+	tokenFileModTime time.Time
+	HTTPClient       *http.Client
+	TokenPolicy      TokenPolicy
+	RetryPolicy      RetryPolicy
 }
 
 // NewClient from a string URL and u/p
-func NewClientSimple(endpoint, username, password string) (Client, error) {
+func NewClientSimple(endpoint, username, password string) (*Client, error) {
 	HTTPClient := &http.Client{}
 	auth := NewAuthUP(username, password)
 
 	apiURL, err := url.Parse(endpoint)
 	if err != nil {
-		return Client{}, err
+		return nil, err
 	}
 
 	return NewClient(apiURL, &auth, HTTPClient)
 }
 
 // NewUnsafeSSLClient that allows self-signed SSL from a string URL and u/p
-func NewUnsafeSSLClient(endpoint, username, password string) (Client, error) {
+func NewUnsafeSSLClient(endpoint, username, password string) (*Client, error) {
 	HTTPClient := &http.Client{
 		Transport: &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
@@ -47,21 +58,23 @@ func NewUnsafeSSLClient(endpoint, username, password string) (Client, error) {
 
 	apiURL, err := url.Parse(endpoint)
 	if err != nil {
-		return Client{}, fmt.Errorf("%w; %s; empty endpoint", ErrCouldNotCreateClient, err)
+		return nil, fmt.Errorf("%w; %s; empty endpoint", ErrCouldNotCreateClient, err)
 	}
 
 	return NewClient(apiURL, &auth, HTTPClient)
 }
 
 // NewClient creates a new MKE API Client from raw components
-func NewClient(apiURL *url.URL, auth *Auth, HTTPClient *http.Client) (Client, error) {
+func NewClient(apiURL *url.URL, auth *Auth, HTTPClient *http.Client) (*Client, error) {
 	if apiURL == nil {
-		return Client{}, fmt.Errorf("%w; empty endpoint", ErrCouldNotCreateClient)
+		return nil, fmt.Errorf("%w; empty endpoint", ErrCouldNotCreateClient)
 	}
-	return Client{
-		apiURL:     apiURL,
-		HTTPClient: HTTPClient,
-		auth:       auth,
+	return &Client{
+		apiURL:      apiURL,
+		HTTPClient:  HTTPClient,
+		auth:        auth,
+		TokenPolicy: NewTokenPolicyDefault(),
+		RetryPolicy: NewRetryPolicyDefault(),
 	}, nil
 }
 
@@ -85,6 +98,19 @@ func (c *Client) reqURLFromTarget(target string) string {
 	return relativeURL.String()
 }
 
+// WithAuth a client for the same MKE API which authenticates separately with
+// other credentials, such as to act as another account
+// The new client shares the http client and policies, but not the auth token.
+func (c *Client) WithAuth(auth *Auth) *Client {
+	return &Client{
+		apiURL:      c.apiURL,
+		auth:        auth,
+		HTTPClient:  c.HTTPClient,
+		TokenPolicy: c.TokenPolicy,
+		RetryPolicy: c.RetryPolicy,
+	}
+}
+
 // Username retrieve username string for auth, so that we don't expose the whole auth struct
 func (c *Client) Username() string {
 	return c.auth.Username
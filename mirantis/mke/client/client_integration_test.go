@@ -63,10 +63,9 @@ func MakeIntegrationClient() (*client.Client, error) {
 		},
 	}
 
-	if tempC, err := client.NewClient(apiURL, &auth, &hc); err != nil {
+	c, err = client.NewClient(apiURL, &auth, &hc)
+	if err != nil {
 		return c, fmt.Errorf("%w; %s", ErrIntergrationClientGenerateError, err)
-	} else {
-		c = &tempC
 	}
 
 	return c, nil
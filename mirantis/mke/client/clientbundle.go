@@ -1,21 +1,64 @@
 package client
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	filenameCAPem      = "ca.pem"
+	filenameCertPem    = "cert.pem"
+	filenamePrivKeyPem = "key.pem"
+	filenamePubKeyPem  = "cert.pub"
+	filenameKubeconfig = "kube.yml"
+	filenameEnvSh      = "env.sh"
+	filenameMetaJSON   = "meta.json"
+
+	envKeyDockerHost = "DOCKER_HOST"
+)
 
-	"gopkg.in/yaml.v2"
+var (
+	ErrFailedToRetrieveClientBundle = errors.New("failed to retrieve the client bundle from MKE")
+	ErrFailedToReadClientBundle     = errors.New("failed to read the client bundle")
 )
 
 // ClientBundle interpretation of the ClientBundle data in memory
 type ClientBundle struct {
-	ID         string            `json:"id"`
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	// Account the bundle belongs to, empty for the account the client logs in as
+	Account    string            `json:"account"`
 	PrivateKey string            `json:"private_key"`
 	PublicKey  string            `json:"public_key"`
 	Cert       string            `json:"cert"`
 	CACert     string            `json:"ca_cert"`
 	Kube       *ClientBundleKube `json:"kube"`
+	Meta       *ClientBundleMeta `json:"meta"`
+	// Env variables exported by the bundle env.sh
+	Env map[string]string `json:"env"`
+	// Files raw contents of every file in the bundle, by path
+	Files map[string]string `json:"files"`
+}
+
+// ClientBundleMeta docker context metadata from the bundle meta.json
+type ClientBundleMeta struct {
+	Name                       string `json:"name"`
+	Description                string `json:"description"`
+	StackOrchestrator          string `json:"stack_orchestrator"`
+	DockerHost                 string `json:"docker_host"`
+	DockerSkipTLSVerify        bool   `json:"docker_skip_tls_verify"`
+	KubernetesHost             string `json:"kubernetes_host"`
+	KubernetesSkipTLSVerify    bool   `json:"kubernetes_skip_tls_verify"`
+	KubernetesDefaultNamespace string `json:"kubernetes_default_namespace"`
 }
 
 // ClientBundleKube Kubernetes parts of the client bundle
@@ -30,6 +73,261 @@ type ClientBundleKube struct {
 	Insecure          string `json:"insecure"`
 }
 
+// NewClientBundleFromZip ClientBundle constructor from the bytes of a client bundle zip
+// as produced by the MKE client bundle API.
+func NewClientBundleFromZip(zipBytes []byte) (ClientBundle, error) {
+	var cb ClientBundle
+
+	zipReader, err := zip.NewReader(bytes.NewReader(zipBytes), int64(len(zipBytes)))
+	if err != nil {
+		return cb, fmt.Errorf("%w; %s", ErrFailedToRetrieveClientBundle, err)
+	}
+
+	cb.ID = zipReader.Comment
+
+	errs := []error{}
+
+	for _, f := range zipReader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		fReader, err := f.Open()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		err = cb.readFile(f.Name, fReader)
+		fReader.Close()
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		errString := ""
+
+		for _, err := range errs {
+			errString = fmt.Sprintf("%s, %s", errString, err)
+		}
+
+		return cb, fmt.Errorf("%w; %s", ErrFailedToRetrieveClientBundle, errString)
+	}
+
+	return cb, nil
+}
+
+// NewClientBundleFromDir ClientBundle constructor from a directory containing
+// an extracted client bundle
+func NewClientBundleFromDir(dir string) (ClientBundle, error) {
+	var cb ClientBundle
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if err := cb.readFile(filepath.ToSlash(name), f); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return cb, fmt.Errorf("%w; %s", ErrFailedToReadClientBundle, err)
+	}
+
+	if cb.Cert == "" || cb.PrivateKey == "" {
+		return cb, fmt.Errorf("%w; %s does not contain a %s and %s", ErrFailedToReadClientBundle, dir, filenameCertPem, filenamePrivKeyPem)
+	}
+
+	return cb, nil
+}
+
+// NewClientBundleFromPath ClientBundle constructor from a path to either a
+// client bundle zip, or a directory containing an extracted bundle
+func NewClientBundleFromPath(path string) (ClientBundle, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ClientBundle{}, fmt.Errorf("%w; %s", ErrFailedToReadClientBundle, err)
+	}
+	if info.IsDir() {
+		return NewClientBundleFromDir(path)
+	}
+
+	zipBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ClientBundle{}, fmt.Errorf("%w; %s", ErrFailedToReadClientBundle, err)
+	}
+	return NewClientBundleFromZip(zipBytes)
+}
+
+// readFile interpret a file from a client bundle, keeping the raw contents of
+// every file
+func (cb *ClientBundle) readFile(name string, r io.Reader) error {
+	if !ValidClientBundleFileName(name) {
+		return fmt.Errorf("%w; %q", ErrUnsafeClientBundleFile, name)
+	}
+
+	val, err := ClientBundleRetrieveValue(r)
+	if err != nil {
+		return err
+	}
+
+	if cb.Files == nil {
+		cb.Files = map[string]string{}
+	}
+	cb.Files[name] = val
+
+	switch name {
+	case filenameCAPem:
+		cb.CACert = val
+	case filenameCertPem:
+		cb.Cert = val
+	case filenamePrivKeyPem:
+		cb.PrivateKey = val
+	case filenamePubKeyPem:
+		cb.PublicKey = val
+	case filenameKubeconfig:
+		kube, err := NewClientBundleKubeFromKubeYml(strings.NewReader(val))
+		if err != nil {
+			return err
+		}
+		cb.Kube = &kube
+	case filenameEnvSh:
+		cb.Env = ClientBundleParseEnvSh(val)
+	case filenameMetaJSON:
+		meta, err := NewClientBundleMetaFromJSON([]byte(val))
+		if err != nil {
+			return err
+		}
+		cb.Meta = &meta
+	}
+	return nil
+}
+
+// DockerHost the docker endpoint for the bundle, from the docker context
+// metadata or the env script
+func (cb ClientBundle) DockerHost() string {
+	if cb.Meta != nil && cb.Meta.DockerHost != "" {
+		return cb.Meta.DockerHost
+	}
+	return cb.Env[envKeyDockerHost]
+}
+
+// accountOr the account the bundle belongs to, or a default for bundles of the
+// logged in account
+func (cb ClientBundle) accountOr(account string) string {
+	if cb.Account != "" {
+		return cb.Account
+	}
+	return account
+}
+
+// ClientBundleParseEnvSh read the exported variables from a bundle env.sh script
+// Lines which are not simple exports (conditionals, commands) are ignored, and
+// shell expressions such as $(pwd) are kept as they are.
+func ClientBundleParseEnvSh(script string) map[string]string {
+	env := map[string]string{}
+
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "export ") {
+			continue
+		}
+		kv := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, "export ")), "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			continue
+		}
+		val := kv[1]
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			val = val[1 : len(val)-1]
+		}
+		env[kv[0]] = val
+	}
+
+	return env
+}
+
+// NewClientBundleMetaFromJSON ClientBundleMeta constructor from the meta.json
+// docker context metadata
+func NewClientBundleMetaFromJSON(metaBytes []byte) (ClientBundleMeta, error) {
+	var meta ClientBundleMeta
+
+	// docker context meta.json format
+	var metaHolder struct {
+		Name     string `json:"Name"`
+		Metadata struct {
+			Description       string `json:"Description"`
+			StackOrchestrator string `json:"StackOrchestrator"`
+		} `json:"Metadata"`
+		Endpoints struct {
+			Docker struct {
+				Host          string `json:"Host"`
+				SkipTLSVerify bool   `json:"SkipTLSVerify"`
+			} `json:"docker"`
+			Kubernetes struct {
+				Host             string `json:"Host"`
+				SkipTLSVerify    bool   `json:"SkipTLSVerify"`
+				DefaultNamespace string `json:"DefaultNamespace"`
+			} `json:"kubernetes"`
+		} `json:"Endpoints"`
+	}
+
+	if err := json.Unmarshal(metaBytes, &metaHolder); err != nil {
+		return meta, err
+	}
+
+	meta.Name = metaHolder.Name
+	meta.Description = metaHolder.Metadata.Description
+	meta.StackOrchestrator = metaHolder.Metadata.StackOrchestrator
+	meta.DockerHost = metaHolder.Endpoints.Docker.Host
+	meta.DockerSkipTLSVerify = metaHolder.Endpoints.Docker.SkipTLSVerify
+	meta.KubernetesHost = metaHolder.Endpoints.Kubernetes.Host
+	meta.KubernetesSkipTLSVerify = metaHolder.Endpoints.Kubernetes.SkipTLSVerify
+	meta.KubernetesDefaultNamespace = metaHolder.Endpoints.Kubernetes.DefaultNamespace
+
+	return meta, nil
+}
+
+// TLSOptions options to connect to MKE using the bundle certificates
+func (cb ClientBundle) TLSOptions() TLSOptions {
+	return TLSOptions{
+		CACertPEM:     cb.CACert,
+		ClientCertPEM: cb.Cert,
+		ClientKeyPEM:  cb.PrivateKey,
+	}
+}
+
+// CertificateInfo metadata of the bundle certificate, such as its expiry
+func (cb ClientBundle) CertificateInfo() (CertificateInfo, error) {
+	ci, err := NewCertificateInfoFromPEM(cb.Cert)
+	if err != nil {
+		return ci, fmt.Errorf("%w; %s", ErrFailedToReadClientBundle, err)
+	}
+	return ci, nil
+}
+
+// CertCommonName the common name of the bundle certificate, which MKE sets to
+// the name of the account that the bundle belongs to
+func (cb ClientBundle) CertCommonName() (string, error) {
+	ci, err := cb.CertificateInfo()
+	if err != nil {
+		return "", err
+	}
+	return ci.CommonName, nil
+}
+
 // ClientBundleRetrieveValue read a value
 func ClientBundleRetrieveValue(val io.Reader) (string, error) {
 	allBytes, err := ioutil.ReadAll(val)
@@ -54,71 +352,30 @@ func NewClientBundleKubeFromKubeYml(val io.Reader) (ClientBundleKube, error) {
 
 	var cbk ClientBundleKube
 
-	// Struct representation of a kube config file.
-	// see https://zhwt.github.io/yaml-to-go/
-	var cbkHolder struct {
-		APIVersion  string            `yaml:"apiVersion"`
-		Kind        string            `yaml:"kind"`
-		Preferences map[string]string `yaml:"preferences"`
-		Clusters    []struct {
-			Name    string `yaml:"name"`
-			Cluster struct {
-				CertificateAuthorityData string `yaml:"certificate-authority-data"`
-				Server                   string `yaml:"server"`
-			} `yaml:"cluster"`
-		} `yaml:"clusters"`
-		Contexts []struct {
-			Name    string `yaml:"name"`
-			Context struct {
-				Cluster string `yaml:"cluster"`
-				User    string `yaml:"user"`
-			} `yaml:"context"`
-		} `yaml:"contexts"`
-		CurrentContext string `yaml:"current-context"`
-		Users          []struct {
-			Name string `yaml:"name"`
-			User struct {
-				ClientCertificateData string `yaml:"client-certificate-data"`
-				ClientKeyData         string `yaml:"client-key-data"`
-			} `yaml:"user"`
-		} `yaml:"users"`
-	}
-
 	cbk.Config = string(k8bytes)
 
-	if err := yaml.UnmarshalStrict(k8bytes, &cbkHolder); err != nil {
+	kubeconfig, err := NewKubeconfigFromYAML(k8bytes)
+	if err != nil {
 		return cbk, err
 	}
 
-	var contextName, clusterName, userName string
+	// a bundle without a complete current context leaves the values empty
+	cluster, user, _ := kubeconfig.ContextClusterUser(kubeconfig.CurrentContext)
 
-	contextName = cbkHolder.CurrentContext
+	cbk.Host = cluster.Server
+	cbk.CACertificate = helperStringBase64Decode(cluster.CertificateAuthorityData)
+	cbk.ClientKey = helperStringBase64Decode(user.ClientKeyData)
+	cbk.ClientCertificate = helperStringBase64Decode(user.ClientCertificateData)
 
-	for _, context := range cbkHolder.Contexts {
-		if context.Name == contextName {
-			clusterName = context.Context.Cluster
-			userName = context.Context.User
-			break
-		}
-	}
-
-	for _, cluster := range cbkHolder.Clusters {
-		if cluster.Name == clusterName {
-			cbk.Host = cluster.Cluster.Server
-			cbk.CACertificate = helperStringBase64Decode(cluster.Cluster.CertificateAuthorityData)
-			break
-		}
-	}
+	return cbk, nil
+}
 
-	for _, user := range cbkHolder.Users {
-		if user.Name == userName {
-			cbk.ClientKey = helperStringBase64Decode(user.User.ClientKeyData)
-			cbk.ClientCertificate = helperStringBase64Decode(user.User.ClientCertificateData)
-			break
-		}
+// Kubeconfig the bundle kubeconfig
+func (cb ClientBundle) Kubeconfig() (Kubeconfig, error) {
+	if cb.Kube == nil || cb.Kube.Config == "" {
+		return Kubeconfig{}, fmt.Errorf("%w; the client bundle has no %s", ErrKubeconfigNotFound, filenameKubeconfig)
 	}
-
-	return cbk, nil
+	return NewKubeconfigFromYAML([]byte(cb.Kube.Config))
 }
 
 // this decodes some strings in the file that are base64 encoded
@@ -2,6 +2,8 @@ package client_test
 
 import (
 	"bytes"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
@@ -56,3 +58,110 @@ func TestClientBundleFromKubeYmlGood(t *testing.T) {
 		t.Errorf("CBK from yaml got the wrong ClientCertificate: %+v", cbk)
 	}
 }
+
+func TestClientBundleFromKubeYmlUnknownFields(t *testing.T) {
+	cbk, err := client.NewClientBundleKubeFromKubeYml(bytes.NewBufferString(MultiContextKubeYml))
+	if err != nil {
+		t.Fatalf("Error converting Kube CB from yaml with unknown fields: %s", err)
+	}
+
+	if cbk.Host != "https://mke.example.com:6443" || cbk.ClientKey != "BCDEFG" || cbk.CACertificate != "EFGHIJ" {
+		t.Errorf("CBK from yaml got the wrong current context values: %+v", cbk)
+	}
+}
+
+func TestClientBundleFromZip(t *testing.T) {
+	cert, key := MockCertificate("myuser")
+	zipBytes := MockClientBundleZip(map[string]string{
+		"ca.pem":   cert,
+		"cert.pem": cert,
+		"key.pem":  key,
+		"cert.pub": "mypublickey",
+		"kube.yml": GoodKubeYml,
+	})
+
+	cb, err := client.NewClientBundleFromZip(zipBytes)
+	if err != nil {
+		t.Fatalf("Error reading client bundle zip: %s", err)
+	}
+
+	if cb.Cert != cert || cb.CACert != cert || cb.PrivateKey != key || cb.PublicKey != "mypublickey" {
+		t.Errorf("Client bundle zip was not read correctly: %+v", cb)
+	}
+	if cb.Kube == nil || cb.Kube.Host != "localhost:6443" {
+		t.Errorf("Client bundle zip kube config was not read: %+v", cb.Kube)
+	}
+	if cn, err := cb.CertCommonName(); err != nil || cn != "myuser" {
+		t.Errorf("Client bundle cert common name was not read: %s %s", cn, err)
+	}
+}
+
+func TestClientBundleFromDir(t *testing.T) {
+	cert, key := MockCertificate("myuser")
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "ca.pem"), []byte(cert), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "cert.pem"), []byte(cert), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "key.pem"), []byte(key), 0600)
+
+	cb, err := client.NewClientBundleFromPath(dir)
+	if err != nil {
+		t.Fatalf("Error reading client bundle directory: %s", err)
+	}
+	if cb.Cert != cert || cb.PrivateKey != key || cb.Kube != nil {
+		t.Errorf("Client bundle directory was not read correctly: %+v", cb)
+	}
+
+	if _, err := client.NewClientBundleFromPath(t.TempDir()); err == nil {
+		t.Error("Empty directory was accepted as a client bundle")
+	}
+}
+
+var (
+	// env.sh as produced by MKE
+	GoodEnvSh = `
+export DOCKER_TLS_VERIFY=1
+export COMPOSE_TLS_VERSION=TLSv1_2
+export DOCKER_CERT_PATH="$(pwd)"
+export DOCKER_HOST=tcp://mke.example.com:443
+
+# kubectl
+if kubectl >/dev/null 2>&1; then
+    unset KUBECONFIG
+    kubectl config --kubeconfig="$(pwd)/kube.yml" use-context mke_admin
+fi
+export KUBECONFIG="$(pwd)/kube.yml"
+`
+	// meta.json docker context as produced by MKE
+	GoodMetaJSON = `{"Name":"mke_admin","Metadata":{"Description":"MKE admin","StackOrchestrator":"swarm"},"Endpoints":{"docker":{"Host":"tcp://mke.example.com:443","SkipTLSVerify":false},"kubernetes":{"Host":"https://mke.example.com:6443","SkipTLSVerify":false,"DefaultNamespace":"default"}}}`
+)
+
+func TestClientBundleFromZipAllFiles(t *testing.T) {
+	cert, key := MockCertificate("myuser")
+	zipBytes := MockClientBundleZip(map[string]string{
+		"ca.pem":              cert,
+		"cert.pem":            cert,
+		"key.pem":             key,
+		"env.sh":              GoodEnvSh,
+		"env.ps1":             "$env:DOCKER_HOST=\"tcp://mke.example.com:443\"",
+		"meta.json":           GoodMetaJSON,
+		"tls/docker/cert.pem": cert,
+	})
+
+	cb, err := client.NewClientBundleFromZip(zipBytes)
+	if err != nil {
+		t.Fatalf("Error reading client bundle zip: %s", err)
+	}
+
+	if len(cb.Files) != 7 || cb.Files["tls/docker/cert.pem"] != cert || cb.Files["env.ps1"] == "" {
+		t.Errorf("Client bundle zip files were not all kept: %+v", cb.Files)
+	}
+	if cb.Env["DOCKER_HOST"] != "tcp://mke.example.com:443" || cb.Env["DOCKER_CERT_PATH"] != "$(pwd)" || cb.Env["KUBECONFIG"] != "$(pwd)/kube.yml" {
+		t.Errorf("Client bundle env.sh was not parsed correctly: %+v", cb.Env)
+	}
+	if cb.Meta == nil || cb.Meta.Name != "mke_admin" || cb.Meta.KubernetesHost != "https://mke.example.com:6443" {
+		t.Errorf("Client bundle meta.json was not parsed correctly: %+v", cb.Meta)
+	}
+	if cb.DockerHost() != "tcp://mke.example.com:443" {
+		t.Errorf("Client bundle has the wrong docker host: %s", cb.DockerHost())
+	}
+}
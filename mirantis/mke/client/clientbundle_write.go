@@ -0,0 +1,206 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/**
+
+# Writing client bundles
+
+A client bundle can be written back out as a directory or a zip with the same
+layout that MKE produces, so that the usual `eval "$(<env.sh)"` works. Private
+keys are written with 0600 permissions, everything else with 0644.
+
+File names come from the bundle zip, so names which are absolute or climb out
+of the directory with ".." are refused rather than written.
+*/
+
+const (
+	ClientBundleDirMode         os.FileMode = 0700
+	ClientBundleFileMode        os.FileMode = 0644
+	ClientBundlePrivateFileMode os.FileMode = 0600
+)
+
+var (
+	ErrFailedToWriteClientBundle = errors.New("failed to write the client bundle")
+	ErrUnsafeClientBundleFile    = errors.New("client bundle file name is outside of the bundle directory")
+)
+
+// FileContents the files of the bundle by path
+// If the bundle was not read from MKE with all of its files, then the files are
+// rebuilt from the bundle values.
+func (cb ClientBundle) FileContents() map[string]string {
+	if len(cb.Files) > 0 {
+		return cb.Files
+	}
+
+	files := map[string]string{}
+	for name, val := range map[string]string{
+		filenameCAPem:      cb.CACert,
+		filenameCertPem:    cb.Cert,
+		filenamePrivKeyPem: cb.PrivateKey,
+		filenamePubKeyPem:  cb.PublicKey,
+	} {
+		if val != "" {
+			files[name] = val
+		}
+	}
+	if cb.Kube != nil && cb.Kube.Config != "" {
+		files[filenameKubeconfig] = cb.Kube.Config
+	}
+	return files
+}
+
+// ClientBundleFileModeFor the permissions for a bundle file, private keys are only
+// readable by the owner
+func ClientBundleFileModeFor(name string) os.FileMode {
+	if path.Base(name) == filenamePrivKeyPem {
+		return ClientBundlePrivateFileMode
+	}
+	return ClientBundleFileMode
+}
+
+// ValidClientBundleFileName is a bundle file name relative, and inside the bundle
+func ValidClientBundleFileName(name string) bool {
+	if name == "" || path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Clean(name)), "/") {
+		if part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// clientBundleFilePath the path of a bundle file in a directory
+func clientBundleFilePath(dir, name string) (string, error) {
+	if !ValidClientBundleFileName(name) {
+		return "", fmt.Errorf("%w; %q", ErrUnsafeClientBundleFile, name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(name)), nil
+}
+
+// WriteDir write the bundle files into a directory, creating it if needed
+func (cb ClientBundle) WriteDir(dir string) error {
+	for name, contents := range cb.FileContents() {
+		filePath, err := clientBundleFilePath(dir, name)
+		if err != nil {
+			return fmt.Errorf("%w; %s", ErrFailedToWriteClientBundle, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(filePath), ClientBundleDirMode); err != nil {
+			return fmt.Errorf("%w; %s", ErrFailedToWriteClientBundle, err)
+		}
+		if err := ioutil.WriteFile(filePath, []byte(contents), ClientBundleFileModeFor(name)); err != nil {
+			return fmt.Errorf("%w; %s", ErrFailedToWriteClientBundle, err)
+		}
+		// WriteFile does not change the mode of an existing file
+		if err := os.Chmod(filePath, ClientBundleFileModeFor(name)); err != nil {
+			return fmt.Errorf("%w; %s", ErrFailedToWriteClientBundle, err)
+		}
+	}
+	return nil
+}
+
+// RemoveDir remove the bundle files from a directory, and the directory if it
+// is then empty. Files that don't exist are ignored.
+func (cb ClientBundle) RemoveDir(dir string) error {
+	subdirs := map[string]bool{}
+
+	for name := range cb.FileContents() {
+		filePath, err := clientBundleFilePath(dir, name)
+		if err != nil {
+			return fmt.Errorf("%w; %s", ErrFailedToWriteClientBundle, err)
+		}
+		if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w; %s", ErrFailedToWriteClientBundle, err)
+		}
+		for d := filepath.Dir(filePath); d != filepath.Clean(dir) && d != "." && d != "/"; d = filepath.Dir(d) {
+			subdirs[d] = true
+		}
+	}
+
+	// remove the deepest directories first, leaving any that still hold files
+	dirs := []string{}
+	for d := range subdirs {
+		dirs = append(dirs, d)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, d := range append(dirs, dir) {
+		os.Remove(d)
+	}
+
+	return nil
+}
+
+// MatchesDir does a directory contain the bundle files, unchanged and with the
+// expected permissions
+func (cb ClientBundle) MatchesDir(dir string) bool {
+	for name, contents := range cb.FileContents() {
+		filePath, err := clientBundleFilePath(dir, name)
+		if err != nil {
+			return false
+		}
+
+		info, err := os.Stat(filePath)
+		if err != nil || info.Mode().Perm() != ClientBundleFileModeFor(name) {
+			return false
+		}
+		b, err := ioutil.ReadFile(filePath)
+		if err != nil || !bytes.Equal(b, []byte(contents)) {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteZip write the bundle as a zip
+func (cb ClientBundle) WriteZip(w io.Writer) error {
+	files := cb.FileContents()
+
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	if cb.ID != "" {
+		if err := zw.SetComment(cb.ID); err != nil {
+			return fmt.Errorf("%w; %s", ErrFailedToWriteClientBundle, err)
+		}
+	}
+
+	for _, name := range names {
+		header := &zip.FileHeader{
+			Name:   name,
+			Method: zip.Deflate,
+		}
+		header.SetMode(ClientBundleFileModeFor(name))
+
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return fmt.Errorf("%w; %s", ErrFailedToWriteClientBundle, err)
+		}
+		if _, err := fw.Write([]byte(files[name])); err != nil {
+			return fmt.Errorf("%w; %s", ErrFailedToWriteClientBundle, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("%w; %s", ErrFailedToWriteClientBundle, err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package client_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestClientBundleWriteDir(t *testing.T) {
+	cert, key := MockCertificate("myuser")
+	cb, err := client.NewClientBundleFromZip(MockClientBundleZip(map[string]string{
+		"ca.pem":              cert,
+		"cert.pem":            cert,
+		"key.pem":             key,
+		"env.sh":              GoodEnvSh,
+		"tls/docker/key.pem":  key,
+		"tls/docker/cert.pem": cert,
+	}))
+	if err != nil {
+		t.Fatalf("Error reading client bundle zip: %s", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "bundle")
+	if err := cb.WriteDir(dir); err != nil {
+		t.Fatalf("Error writing client bundle directory: %s", err)
+	}
+
+	for name, mode := range map[string]os.FileMode{
+		"key.pem":            client.ClientBundlePrivateFileMode,
+		"tls/docker/key.pem": client.ClientBundlePrivateFileMode,
+		"cert.pem":           client.ClientBundleFileMode,
+		"env.sh":             client.ClientBundleFileMode,
+	} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Client bundle file %s was not written: %s", name, err)
+		}
+		if info.Mode().Perm() != mode {
+			t.Errorf("Client bundle file %s has the wrong mode: %s", name, info.Mode())
+		}
+	}
+
+	if readCB, err := client.NewClientBundleFromDir(dir); err != nil {
+		t.Errorf("Written client bundle could not be read: %s", err)
+	} else if readCB.PrivateKey != key || readCB.Env["DOCKER_HOST"] != cb.Env["DOCKER_HOST"] {
+		t.Errorf("Written client bundle does not match: %+v", readCB)
+	}
+
+	if !cb.MatchesDir(dir) {
+		t.Error("Written client bundle directory did not match")
+	}
+	ioutil.WriteFile(filepath.Join(dir, "env.sh"), []byte("changed"), client.ClientBundleFileMode)
+	if cb.MatchesDir(dir) {
+		t.Error("Changed client bundle directory still matched")
+	}
+	cb.WriteDir(dir)
+	os.Chmod(filepath.Join(dir, "key.pem"), 0644)
+	if cb.MatchesDir(dir) {
+		t.Error("Client bundle directory with a readable key still matched")
+	}
+
+	if err := cb.RemoveDir(dir); err != nil {
+		t.Fatalf("Error removing client bundle directory: %s", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Client bundle directory was not removed: %s", err)
+	}
+}
+
+func TestClientBundleWriteZip(t *testing.T) {
+	cert, key := MockCertificate("myuser")
+	cb := client.ClientBundle{
+		ID:         "mybundle",
+		Cert:       cert,
+		CACert:     cert,
+		PrivateKey: key,
+		PublicKey:  "mypublickey",
+	}
+
+	buf := new(bytes.Buffer)
+	if err := cb.WriteZip(buf); err != nil {
+		t.Fatalf("Error writing client bundle zip: %s", err)
+	}
+
+	readCB, err := client.NewClientBundleFromZip(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Written client bundle zip could not be read: %s", err)
+	}
+	if readCB.ID != cb.ID || readCB.PrivateKey != key || readCB.PublicKey != cb.PublicKey || readCB.CACert != cert {
+		t.Errorf("Written client bundle zip does not match: %+v", readCB)
+	}
+}
+
+func TestClientBundleUnsafeFileNames(t *testing.T) {
+	for _, name := range []string{"../outside.pem", "tls/../../outside.pem", "/etc/outside.pem", ".."} {
+		if client.ValidClientBundleFileName(name) {
+			t.Errorf("Unsafe client bundle file name %q was accepted", name)
+		}
+
+		if _, err := client.NewClientBundleFromZip(MockClientBundleZip(map[string]string{name: "hostile"})); !errors.Is(err, client.ErrFailedToRetrieveClientBundle) {
+			t.Errorf("Client bundle zip with the file %q was not rejected: %s", name, err)
+		}
+
+		root := t.TempDir()
+		dir := filepath.Join(root, "a", "bundle")
+		cb := client.ClientBundle{Files: map[string]string{name: "hostile"}}
+
+		if err := cb.WriteDir(dir); !errors.Is(err, client.ErrFailedToWriteClientBundle) {
+			t.Errorf("Writing the file %q was not refused: %s", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "a", "outside.pem")); err == nil {
+			t.Errorf("The file %q was written outside of the bundle directory", name)
+		}
+		if err := cb.RemoveDir(dir); !errors.Is(err, client.ErrFailedToWriteClientBundle) {
+			t.Errorf("Removing the file %q was not refused: %s", name, err)
+		}
+		if cb.MatchesDir(dir) {
+			t.Errorf("The file %q matched a directory", name)
+		}
+	}
+
+	for _, name := range []string{"key.pem", "tls/docker/key.pem", "tls/../key.pem"} {
+		if !client.ValidClientBundleFileName(name) {
+			t.Errorf("Client bundle file name %q was refused", name)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -11,16 +12,85 @@ This is tested via the api_genericrequest.go public methods
 */
 
 // doAuthorizedRequest perform an http request for an endpoint that requires auth
+// If MKE rejects the token then we log in again and replay the request once.
 func (c *Client) doAuthorizedRequest(req *http.Request) (*Response, error) {
 	if err := c.authorizeRequest(req); err != nil {
 		return nil, err
 	}
 
-	return c.doRequest(req)
+	res, err := c.doRequest(req)
+	if err == nil || !errors.Is(err, ErrUnauthorizedReq) {
+		return res, err
+	}
+	if c.auth.Mode == AuthModeToken {
+		return res, fmt.Errorf("%w; %s", ErrTokenExpired, err)
+	}
+	if !c.auth.canLogin() {
+		return res, err
+	}
+
+	replayReq, rewindErr := rewindRequest(req)
+	if rewindErr != nil {
+		// we can't send the body again, so report the original failure
+		return res, err
+	}
+
+	c.invalidateToken(req)
+	if err := c.authorizeRequest(replayReq); err != nil {
+		return nil, err
+	}
+
+	return c.doRequest(replayReq)
 }
 
-// doRequest perform http request, catch http errors and return body as io.ReaderCloser
+// rewindRequest copy a request so that it can be sent again, including its body
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	rewound := req.Clone(req.Context())
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return rewound, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("%w; request body cannot be replayed", ErrRequestCreation)
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("%w; %s", ErrRequestCreation, err)
+	}
+	rewound.Body = body
+
+	return rewound, nil
+}
+
+// doRequest perform http request, retrying transient failures according to the
+// client RetryPolicy
 func (c *Client) doRequest(req *http.Request) (*Response, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := c.sendRequest(req)
+
+		delay, retry := c.RetryPolicy.retryDelay(req, res, err, attempt)
+		if !retry {
+			return res, err
+		}
+
+		retryReq, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
+			return res, err
+		}
+		if res != nil {
+			res.Body.Close()
+		}
+		if waitErr := waitForRetry(req.Context(), delay); waitErr != nil {
+			return res, err
+		}
+
+		req = retryReq
+	}
+}
+
+// sendRequest perform http request, catch http errors and return body as io.ReaderCloser
+func (c *Client) sendRequest(req *http.Request) (*Response, error) {
 	apiRes, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -32,18 +102,7 @@ func (c *Client) doRequest(req *http.Request) (*Response, error) {
 
 	if res.StatusCode >= http.StatusBadRequest {
 		b, _ := ioutil.ReadAll(res.Body)
-
-		if res.StatusCode == http.StatusUnauthorized {
-			return res, fmt.Errorf("%w: Unauthorized: %d : %s", ErrUnauthorizedReq, res.StatusCode, b)
-		}
-		if res.StatusCode == http.StatusNotFound {
-			return res, fmt.Errorf("%w: Not Found: %d : %s", ErrUnknownTarget, res.StatusCode, b)
-		}
-		if res.StatusCode == http.StatusInternalServerError {
-			return res, fmt.Errorf("%w: Server Error: %d : %s", ErrServerError, res.StatusCode, b)
-		}
-
-		return res, fmt.Errorf("%w: Status code: %d : %s", ErrResponseError, res.StatusCode, b)
+		return res, NewAPIError(apiRes, b)
 	}
 
 	return res, nil
@@ -15,4 +15,10 @@ var (
 	ErrServerError       = errors.New("server error occured")
 	ErrEmptyStruct       = errors.New("empty struct passed in MKE client")
 	ErrInvalidFilter     = errors.New("passing invalid account retrieval filter in MKE client")
+	ErrCannotLogin       = errors.New("MKE client auth mode does not support logging in")
+	ErrTokenExpired      = errors.New("MKE rejected the auth token, it has probably expired and a new token is needed")
+	ErrTokenFile         = errors.New("could not read the MKE auth token file")
+	ErrMFARequired       = errors.New("MKE account requires a two factor code to log in")
+	ErrMFACodeRejected   = errors.New("MKE rejected the two factor code")
+	ErrUnknownOperation  = errors.New("unknown MKE role operation")
 )
@@ -0,0 +1,334 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v2"
+)
+
+/**
+
+# Kubeconfig
+
+Client bundles include a kube.yml kubeconfig, which authenticates with the
+bundle certificate. Kubeconfig models the parts of the kubeconfig format that
+matter for MKE, so that the configs of several bundles can be merged into one,
+contexts renamed, and users switched to a token or an exec credential plugin
+instead of embedded keys.
+
+Parsing is tolerant: fields which are not modelled (such as auth-provider,
+proxy-url, extensions, or those added by newer MKE or kubectl versions) are
+kept as they are in the Extra of each entry, and written back out with it, so
+that user supplied kubeconfigs survive being merged or renamed.
+
+@see https://kubernetes.io/docs/concepts/configuration/organize-cluster-access-kubeconfig/
+*/
+
+const (
+	KubeconfigAPIVersion = "v1"
+	KubeconfigKind       = "Config"
+
+	// KubeconfigExecAPIVersion default client authentication API for exec plugins
+	KubeconfigExecAPIVersion = "client.authentication.k8s.io/v1beta1"
+)
+
+var (
+	ErrInvalidKubeconfig  = errors.New("could not parse the kubeconfig")
+	ErrKubeconfigConflict = errors.New("kubeconfigs have different entries with the same name")
+	ErrKubeconfigNotFound = errors.New("kubeconfig entry was not found")
+)
+
+// Kubeconfig a kubernetes client configuration
+type Kubeconfig struct {
+	APIVersion     string                   `yaml:"apiVersion"`
+	Kind           string                   `yaml:"kind"`
+	Preferences    map[string]interface{}   `yaml:"preferences"`
+	Clusters       []KubeconfigNamedCluster `yaml:"clusters"`
+	Contexts       []KubeconfigNamedContext `yaml:"contexts"`
+	CurrentContext string                   `yaml:"current-context"`
+	Users          []KubeconfigNamedUser    `yaml:"users"`
+
+	// Extra fields which are not modelled, kept as they are
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigNamedCluster a named kubeconfig cluster entry
+type KubeconfigNamedCluster struct {
+	Name    string            `yaml:"name"`
+	Cluster KubeconfigCluster `yaml:"cluster"`
+
+	// Extra fields which are not modelled, kept as they are
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigCluster how to reach a kubernetes API
+type KubeconfigCluster struct {
+	Server                   string `yaml:"server"`
+	CertificateAuthorityData string `yaml:"certificate-authority-data,omitempty"`
+	CertificateAuthority     string `yaml:"certificate-authority,omitempty"`
+	InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify,omitempty"`
+	TLSServerName            string `yaml:"tls-server-name,omitempty"`
+
+	// Extra fields which are not modelled, kept as they are
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigNamedContext a named kubeconfig context entry
+type KubeconfigNamedContext struct {
+	Name    string            `yaml:"name"`
+	Context KubeconfigContext `yaml:"context"`
+
+	// Extra fields which are not modelled, kept as they are
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigContext a cluster and user pair
+type KubeconfigContext struct {
+	Cluster   string `yaml:"cluster"`
+	User      string `yaml:"user"`
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Extra fields which are not modelled, kept as they are
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigNamedUser a named kubeconfig user entry
+type KubeconfigNamedUser struct {
+	Name string         `yaml:"name"`
+	User KubeconfigUser `yaml:"user"`
+
+	// Extra fields which are not modelled, kept as they are
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigUser credentials for a kubernetes API
+// Certificate and key data are base64 encoded PEMs.
+type KubeconfigUser struct {
+	ClientCertificateData string          `yaml:"client-certificate-data,omitempty"`
+	ClientKeyData         string          `yaml:"client-key-data,omitempty"`
+	ClientCertificate     string          `yaml:"client-certificate,omitempty"`
+	ClientKey             string          `yaml:"client-key,omitempty"`
+	Token                 string          `yaml:"token,omitempty"`
+	TokenFile             string          `yaml:"tokenFile,omitempty"`
+	Exec                  *KubeconfigExec `yaml:"exec,omitempty"`
+
+	// Extra fields which are not modelled, kept as they are
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigExec an exec credential plugin which provides user credentials
+type KubeconfigExec struct {
+	APIVersion         string              `yaml:"apiVersion"`
+	Command            string              `yaml:"command"`
+	Args               []string            `yaml:"args,omitempty"`
+	Env                []KubeconfigExecEnv `yaml:"env,omitempty"`
+	InstallHint        string              `yaml:"installHint,omitempty"`
+	ProvideClusterInfo bool                `yaml:"provideClusterInfo,omitempty"`
+	InteractiveMode    string              `yaml:"interactiveMode,omitempty"`
+
+	// Extra fields which are not modelled, kept as they are
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigExecEnv an environment variable for an exec credential plugin
+type KubeconfigExecEnv struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+
+	// Extra fields which are not modelled, kept as they are
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// NewKubeconfig empty Kubeconfig constructor
+func NewKubeconfig() Kubeconfig {
+	return Kubeconfig{
+		APIVersion:  KubeconfigAPIVersion,
+		Kind:        KubeconfigKind,
+		Preferences: map[string]interface{}{},
+	}
+}
+
+// NewKubeconfigFromYAML Kubeconfig constructor from kubeconfig yaml
+func NewKubeconfigFromYAML(kubeYml []byte) (Kubeconfig, error) {
+	k := NewKubeconfig()
+	if err := yaml.Unmarshal(kubeYml, &k); err != nil {
+		return k, fmt.Errorf("%w; %s", ErrInvalidKubeconfig, err)
+	}
+	return k, nil
+}
+
+// YAML the kubeconfig as yaml
+func (k Kubeconfig) YAML() ([]byte, error) {
+	return yaml.Marshal(k)
+}
+
+// Cluster find a cluster by name
+func (k Kubeconfig) Cluster(name string) (KubeconfigCluster, bool) {
+	for _, c := range k.Clusters {
+		if c.Name == name {
+			return c.Cluster, true
+		}
+	}
+	return KubeconfigCluster{}, false
+}
+
+// Context find a context by name
+func (k Kubeconfig) Context(name string) (KubeconfigContext, bool) {
+	for _, c := range k.Contexts {
+		if c.Name == name {
+			return c.Context, true
+		}
+	}
+	return KubeconfigContext{}, false
+}
+
+// User find a user by name
+func (k Kubeconfig) User(name string) (KubeconfigUser, bool) {
+	for _, u := range k.Users {
+		if u.Name == name {
+			return u.User, true
+		}
+	}
+	return KubeconfigUser{}, false
+}
+
+// ContextClusterUser the cluster and user of a context
+func (k Kubeconfig) ContextClusterUser(name string) (KubeconfigCluster, KubeconfigUser, error) {
+	context, ok := k.Context(name)
+	if !ok {
+		return KubeconfigCluster{}, KubeconfigUser{}, fmt.Errorf("%w; context %s", ErrKubeconfigNotFound, name)
+	}
+	cluster, ok := k.Cluster(context.Cluster)
+	if !ok {
+		return KubeconfigCluster{}, KubeconfigUser{}, fmt.Errorf("%w; cluster %s", ErrKubeconfigNotFound, context.Cluster)
+	}
+	user, ok := k.User(context.User)
+	if !ok {
+		return cluster, KubeconfigUser{}, fmt.Errorf("%w; user %s", ErrKubeconfigNotFound, context.User)
+	}
+	return cluster, user, nil
+}
+
+// RenameContext rename a context, along with its cluster and user when no other
+// context uses them, so that kubeconfigs which used the same names can be merged
+func (k *Kubeconfig) RenameContext(oldName, newName string) error {
+	index := -1
+	for i, c := range k.Contexts {
+		if c.Name == oldName {
+			index = i
+		}
+		if c.Name == newName && newName != oldName {
+			return fmt.Errorf("%w; context %s already exists", ErrKubeconfigConflict, newName)
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("%w; context %s", ErrKubeconfigNotFound, oldName)
+	}
+
+	context := k.Contexts[index].Context
+	clusterShared, userShared := false, false
+	for i, c := range k.Contexts {
+		if i == index {
+			continue
+		}
+		clusterShared = clusterShared || c.Context.Cluster == context.Cluster
+		userShared = userShared || c.Context.User == context.User
+	}
+
+	if _, exists := k.Cluster(newName); !clusterShared && !exists {
+		for i := range k.Clusters {
+			if k.Clusters[i].Name == context.Cluster {
+				k.Clusters[i].Name = newName
+				k.Contexts[index].Context.Cluster = newName
+			}
+		}
+	}
+	if _, exists := k.User(newName); !userShared && !exists {
+		for i := range k.Users {
+			if k.Users[i].Name == context.User {
+				k.Users[i].Name = newName
+				k.Contexts[index].Context.User = newName
+			}
+		}
+	}
+
+	k.Contexts[index].Name = newName
+	if k.CurrentContext == oldName {
+		k.CurrentContext = newName
+	}
+	return nil
+}
+
+// UseToken replace the credentials of a user with a bearer token
+func (k *Kubeconfig) UseToken(userName, token string) error {
+	return k.setUser(userName, KubeconfigUser{Token: token})
+}
+
+// UseExec replace the credentials of a user with an exec credential plugin
+func (k *Kubeconfig) UseExec(userName string, exec KubeconfigExec) error {
+	if exec.APIVersion == "" {
+		exec.APIVersion = KubeconfigExecAPIVersion
+	}
+	return k.setUser(userName, KubeconfigUser{Exec: &exec})
+}
+
+func (k *Kubeconfig) setUser(name string, user KubeconfigUser) error {
+	for i := range k.Users {
+		if k.Users[i].Name == name {
+			k.Users[i].User = user
+			return nil
+		}
+	}
+	return fmt.Errorf("%w; user %s", ErrKubeconfigNotFound, name)
+}
+
+// MergeKubeconfigs combine kubeconfigs into one
+// Entries with the same name must be identical, otherwise the contexts should
+// be renamed first. The current context, and any other top level fields which
+// are not modelled, are those of the first kubeconfig which has them.
+func MergeKubeconfigs(configs ...Kubeconfig) (Kubeconfig, error) {
+	merged := NewKubeconfig()
+
+	for _, k := range configs {
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = k.CurrentContext
+		}
+		for key, val := range k.Preferences {
+			merged.Preferences[key] = val
+		}
+		for key, val := range k.Extra {
+			if _, ok := merged.Extra[key]; !ok {
+				if merged.Extra == nil {
+					merged.Extra = map[string]interface{}{}
+				}
+				merged.Extra[key] = val
+			}
+		}
+
+		for _, c := range k.Clusters {
+			if existing, ok := merged.Cluster(c.Name); !ok {
+				merged.Clusters = append(merged.Clusters, c)
+			} else if !reflect.DeepEqual(existing, c.Cluster) {
+				return merged, fmt.Errorf("%w; cluster %s", ErrKubeconfigConflict, c.Name)
+			}
+		}
+		for _, c := range k.Contexts {
+			if existing, ok := merged.Context(c.Name); !ok {
+				merged.Contexts = append(merged.Contexts, c)
+			} else if !reflect.DeepEqual(existing, c.Context) {
+				return merged, fmt.Errorf("%w; context %s", ErrKubeconfigConflict, c.Name)
+			}
+		}
+		for _, u := range k.Users {
+			if existing, ok := merged.User(u.Name); !ok {
+				merged.Users = append(merged.Users, u)
+			} else if !reflect.DeepEqual(existing, u.User) {
+				return merged, fmt.Errorf("%w; user %s", ErrKubeconfigConflict, u.Name)
+			}
+		}
+	}
+
+	return merged, nil
+}
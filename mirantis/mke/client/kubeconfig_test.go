@@ -0,0 +1,233 @@
+package client_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+var (
+	// a kubeconfig with two contexts and fields which are not modelled
+	MultiContextKubeYml = `
+apiVersion: v1
+kind: Config
+preferences: {}
+clusters:
+- name: mke
+  cluster:
+    certificate-authority-data: RUZHSElK
+    server: https://mke.example.com:6443
+    extensions:
+    - name: newer-mke
+      extension: {}
+contexts:
+- name: admin
+  context:
+    cluster: mke
+    user: admin
+- name: ci
+  context:
+    cluster: mke
+    user: ci
+    namespace: ci
+current-context: admin
+users:
+- name: admin
+  user:
+    client-certificate-data: QUJDREU=
+    client-key-data: QkNERUZH
+- name: ci
+  user:
+    token: citoken
+`
+)
+
+func TestKubeconfigFromYAML(t *testing.T) {
+	k, err := client.NewKubeconfigFromYAML([]byte(MultiContextKubeYml))
+	if err != nil {
+		t.Fatalf("Error parsing a kubeconfig with unknown fields: %s", err)
+	}
+
+	if len(k.Clusters) != 1 || len(k.Contexts) != 2 || len(k.Users) != 2 {
+		t.Fatalf("Kubeconfig entries were not all parsed: %+v", k)
+	}
+
+	cluster, user, err := k.ContextClusterUser("ci")
+	if err != nil {
+		t.Fatalf("Could not find the ci context: %s", err)
+	}
+	if cluster.Server != "https://mke.example.com:6443" || user.Token != "citoken" {
+		t.Errorf("Wrong cluster or user for the ci context: %+v %+v", cluster, user)
+	}
+
+	if _, _, err := k.ContextClusterUser("nocontext"); !errors.Is(err, client.ErrKubeconfigNotFound) {
+		t.Errorf("Wrong error for a missing context: %s", err)
+	}
+
+	if _, err := client.NewKubeconfigFromYAML([]byte("clusters: {")); !errors.Is(err, client.ErrInvalidKubeconfig) {
+		t.Errorf("Wrong error for invalid yaml: %s", err)
+	}
+}
+
+func TestKubeconfigRoundTripKeepsUnknownFields(t *testing.T) {
+	kubeYml := `
+apiVersion: v1
+kind: Config
+clusters:
+- name: mke
+  cluster:
+    server: https://mke.example.com:6443
+    certificate-authority: /etc/mke/ca.pem
+    proxy-url: http://proxy.example.com:3128
+    extensions:
+    - name: newer-mke
+      extension:
+        setting: cluster
+contexts:
+- name: admin
+  context:
+    cluster: mke
+    user: admin
+    extensions:
+    - name: newer-mke
+      extension:
+        setting: context
+current-context: admin
+users:
+- name: admin
+  user:
+    auth-provider:
+      name: oidc
+      config:
+        idp-issuer-url: https://idp.example.com
+extensions:
+- name: newer-mke
+  extension:
+    setting: config
+`
+
+	k, err := client.NewKubeconfigFromYAML([]byte(kubeYml))
+	if err != nil {
+		t.Fatalf("Error parsing a kubeconfig with unknown fields: %s", err)
+	}
+	if err := k.RenameContext("admin", "mke-admin"); err != nil {
+		t.Fatalf("Could not rename the context: %s", err)
+	}
+	merged, err := client.MergeKubeconfigs(k)
+	if err != nil {
+		t.Fatalf("Could not merge the kubeconfig: %s", err)
+	}
+
+	out, err := merged.YAML()
+	if err != nil {
+		t.Fatalf("Could not write the kubeconfig: %s", err)
+	}
+	for _, expected := range []string{
+		"proxy-url: http://proxy.example.com:3128",
+		"certificate-authority: /etc/mke/ca.pem",
+		"setting: cluster",
+		"setting: context",
+		"setting: config",
+		"idp-issuer-url: https://idp.example.com",
+	} {
+		if !strings.Contains(string(out), expected) {
+			t.Errorf("Kubeconfig lost %q in a round trip:\n%s", expected, out)
+		}
+	}
+
+	again, err := client.NewKubeconfigFromYAML(out)
+	if err != nil {
+		t.Fatalf("Could not parse the written kubeconfig: %s", err)
+	}
+	if user, ok := again.User("mke-admin"); !ok || user.Extra["auth-provider"] == nil {
+		t.Errorf("Renamed user lost its auth-provider: %+v", again.Users)
+	}
+}
+
+func TestKubeconfigRenameAndMerge(t *testing.T) {
+	first, _ := client.NewKubeconfigFromYAML([]byte(GoodKubeYml))
+	second, _ := client.NewKubeconfigFromYAML([]byte(GoodKubeYml))
+	second.Users[0].User.ClientKeyData = "T1RIRVI="
+
+	// the same bundle twice merges to itself
+	if merged, err := client.MergeKubeconfigs(first, first); err != nil || len(merged.Contexts) != 1 {
+		t.Errorf("Merging identical kubeconfigs failed: %s, %+v", err, merged)
+	}
+
+	if _, err := client.MergeKubeconfigs(first, second); !errors.Is(err, client.ErrKubeconfigConflict) {
+		t.Errorf("Wrong error merging conflicting kubeconfigs: %s", err)
+	}
+
+	if err := second.RenameContext("6443_admin", "other"); err != nil {
+		t.Fatalf("Could not rename the context: %s", err)
+	}
+	if second.CurrentContext != "other" || second.Clusters[0].Name != "other" || second.Users[0].Name != "other" {
+		t.Errorf("Rename did not rename the context entries: %+v", second)
+	}
+
+	merged, err := client.MergeKubeconfigs(first, second)
+	if err != nil {
+		t.Fatalf("Merging renamed kubeconfigs failed: %s", err)
+	}
+	if len(merged.Contexts) != 2 || len(merged.Clusters) != 2 || len(merged.Users) != 2 || merged.CurrentContext != "6443_admin" {
+		t.Errorf("Merged kubeconfig has the wrong entries: %+v", merged)
+	}
+	if _, user, err := merged.ContextClusterUser("other"); err != nil || user.ClientKeyData != "T1RIRVI=" {
+		t.Errorf("Merged kubeconfig has the wrong user for the renamed context: %+v, %s", user, err)
+	}
+
+	if err := merged.RenameContext("other", "6443_admin"); !errors.Is(err, client.ErrKubeconfigConflict) {
+		t.Errorf("Wrong error renaming to an existing context: %s", err)
+	}
+}
+
+func TestKubeconfigRenameSharedCluster(t *testing.T) {
+	k, _ := client.NewKubeconfigFromYAML([]byte(MultiContextKubeYml))
+
+	if err := k.RenameContext("ci", "ci-renamed"); err != nil {
+		t.Fatalf("Could not rename the context: %s", err)
+	}
+
+	context, _ := k.Context("ci-renamed")
+	if context.Cluster != "mke" || context.User != "ci-renamed" {
+		t.Errorf("Rename changed a shared cluster, or not the user: %+v", context)
+	}
+}
+
+func TestKubeconfigUseTokenAndExec(t *testing.T) {
+	k, _ := client.NewKubeconfigFromYAML([]byte(GoodKubeYml))
+
+	if err := k.UseToken("6443_admin_user", "mytoken"); err != nil {
+		t.Fatalf("Could not set a token: %s", err)
+	}
+	if user, _ := k.User("6443_admin_user"); user.Token != "mytoken" || user.ClientKeyData != "" {
+		t.Errorf("Token did not replace the user keys: %+v", user)
+	}
+
+	if err := k.UseExec("6443_admin_user", client.KubeconfigExec{Command: "mke-login", Args: []string{"--user", "admin"}}); err != nil {
+		t.Fatalf("Could not set an exec plugin: %s", err)
+	}
+
+	kubeYml, err := k.YAML()
+	if err != nil {
+		t.Fatalf("Could not write the kubeconfig: %s", err)
+	}
+	if strings.Contains(string(kubeYml), "client-key-data") || strings.Contains(string(kubeYml), "mytoken") {
+		t.Errorf("Written kubeconfig still has the old credentials:\n%s", kubeYml)
+	}
+
+	written, err := client.NewKubeconfigFromYAML(kubeYml)
+	if err != nil {
+		t.Fatalf("Could not read the written kubeconfig: %s", err)
+	}
+	user, _ := written.User("6443_admin_user")
+	if user.Exec == nil || user.Exec.Command != "mke-login" || user.Exec.APIVersion != client.KubeconfigExecAPIVersion {
+		t.Errorf("Written kubeconfig has the wrong exec plugin: %+v", user.Exec)
+	}
+
+	if err := k.UseToken("nouser", "mytoken"); !errors.Is(err, client.ErrKubeconfigNotFound) {
+		t.Errorf("Wrong error for a missing user: %s", err)
+	}
+}
@@ -0,0 +1,260 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+/**
+
+# Request logging
+
+LoggingRoundTripper can wrap the http client transport to log every MKE API
+request through tflog, so that it shows up with TF_LOG=DEBUG (method, target,
+status and latency) or TF_LOG=TRACE (headers, and bodies if LogBodies is set).
+Bodies are only buffered and redacted when LogBodies is set, so it should only
+be set when they will actually be logged.
+
+Secrets are redacted before anything is logged:
+- the Authorization header (and cookies),
+- secret fields in JSON bodies, such as the auth/login password and token, and
+  the two factor code of login requests (but not the error code of responses),
+- PEM private keys in any text body,
+- the contents of zip bodies (client bundles contain the private key), of
+  which only the file names and sizes are logged.
+*/
+
+const (
+	redactedValue = "<redacted>"
+)
+
+var (
+	// header keys whose values are never logged
+	redactedHeaderKeys = []string{HeaderKeyAuthorization, "Cookie", "Set-Cookie"}
+	// JSON body keys whose values are never logged
+	redactedJSONKeys = map[string]bool{
+		"password":    true,
+		"oldPassword": true,
+		"newPassword": true,
+		"token":       true,
+		"auth_token":  true,
+		"privateKey":  true,
+		"totpSecret":  true,
+	}
+	// JSON request body keys whose values are never logged, on top of the
+	// redactedJSONKeys; "code" is also the MKE error code in response bodies
+	redactedRequestJSONKeys = map[string]bool{
+		"code": true,
+	}
+
+	pemPrivateKeyRegexp = regexp.MustCompile(`-----BEGIN ([A-Z ]*)PRIVATE KEY-----[\s\S]*?-----END ([A-Z ]*)PRIVATE KEY-----`)
+)
+
+// LoggingRoundTripper http.RoundTripper which logs MKE API traffic with secrets redacted
+type LoggingRoundTripper struct {
+	Next http.RoundTripper
+	// LogBodies log the (redacted) request and response bodies, which means
+	// buffering them in memory
+	LogBodies bool
+}
+
+// NewLoggingRoundTripper LoggingRoundTripper constructor wrapping a transport
+// if next is nil then the http.DefaultTransport is used.
+func NewLoggingRoundTripper(next http.RoundTripper) *LoggingRoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &LoggingRoundTripper{Next: next}
+}
+
+// RoundTrip log the request, pass it on to the next transport and log the response
+func (lrt *LoggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	reqFields := []interface{}{
+		"method", req.Method,
+		"target", req.URL.Path,
+		"headers", RedactHeaders(req.Header),
+	}
+	if lrt.LogBodies {
+		peekedReq, reqBody, err := peekRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		req = peekedReq
+		reqFields = append(reqFields, "body", RedactRequestBody(req.Header.Get("Content-Type"), reqBody))
+	}
+
+	tflog.Trace(ctx, "MKE API request", reqFields...)
+
+	start := time.Now()
+	res, err := lrt.Next.RoundTrip(req)
+	latency := time.Since(start)
+
+	if err != nil {
+		tflog.Debug(ctx, "MKE API request failed",
+			"method", req.Method,
+			"target", req.URL.Path,
+			"latency", latency.String(),
+			"error", err.Error(),
+		)
+		return res, err
+	}
+
+	tflog.Debug(ctx, "MKE API request",
+		"method", req.Method,
+		"target", req.URL.Path,
+		"status", res.StatusCode,
+		"latency", latency.String(),
+	)
+
+	resFields := []interface{}{
+		"method", req.Method,
+		"target", req.URL.Path,
+		"status", res.StatusCode,
+		"headers", RedactHeaders(res.Header),
+	}
+	if lrt.LogBodies {
+		resBody, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+		if err != nil {
+			return res, err
+		}
+		resFields = append(resFields, "body", RedactBody(res.Header.Get("Content-Type"), resBody))
+	}
+
+	tflog.Trace(ctx, "MKE API response", resFields...)
+
+	return res, nil
+}
+
+// peekRequestBody read the request body without consuming it
+//
+// A RoundTripper may not modify the request, so if the body can't be
+// retrieved again then a clone of the request with a replayable body is
+// returned, which should be sent on in place of the original.
+func peekRequestBody(req *http.Request) (*http.Request, []byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, []byte{}, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return req, nil, err
+		}
+		defer body.Close()
+		b, err := ioutil.ReadAll(body)
+		return req, b, err
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return req, nil, err
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = ioutil.NopCloser(bytes.NewReader(b))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+	return clone, b, nil
+}
+
+// RedactHeaders flatten http headers for logging, with secret values redacted
+func RedactHeaders(headers http.Header) map[string]string {
+	redacted := map[string]string{}
+	for key, vals := range headers {
+		redacted[key] = strings.Join(vals, ", ")
+	}
+	for _, key := range redactedHeaderKeys {
+		if val := headers.Get(key); val != "" {
+			if strings.HasPrefix(val, "Bearer ") {
+				redacted[http.CanonicalHeaderKey(key)] = "Bearer " + redactedValue
+			} else {
+				redacted[http.CanonicalHeaderKey(key)] = redactedValue
+			}
+		}
+	}
+	return redacted
+}
+
+// RedactBody convert a response body to a string for logging, with secrets
+// redacted
+func RedactBody(contentType string, body []byte) string {
+	return redactBody(contentType, body, nil)
+}
+
+// RedactRequestBody convert a request body to a string for logging, with
+// secrets redacted, including the two factor codes which only requests carry
+func RedactRequestBody(contentType string, body []byte) string {
+	return redactBody(contentType, body, redactedRequestJSONKeys)
+}
+
+// redactBody convert a body to a string for logging, redacting the extra JSON
+// keys on top of the redactedJSONKeys
+func redactBody(contentType string, body []byte, extraKeys map[string]bool) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if strings.Contains(contentType, "zip") || bytes.HasPrefix(body, []byte("PK\x03\x04")) {
+		return redactZipBody(body)
+	}
+
+	var jsonBody interface{}
+	if err := json.Unmarshal(body, &jsonBody); err == nil {
+		if redactedBytes, err := json.Marshal(redactJSONValue(jsonBody, extraKeys)); err == nil {
+			body = redactedBytes
+		}
+	}
+
+	return pemPrivateKeyRegexp.ReplaceAllString(string(body), "-----BEGIN ${1}PRIVATE KEY-----"+redactedValue+"-----END ${2}PRIVATE KEY-----")
+}
+
+// redactZipBody describe the files in a zip, without any of their content
+func redactZipBody(body []byte) string {
+	zipReader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return fmt.Sprintf("<zip: %d bytes>", len(body))
+	}
+
+	files := []string{}
+	for _, f := range zipReader.File {
+		files = append(files, fmt.Sprintf("%s (%d bytes)", f.Name, f.UncompressedSize64))
+	}
+	return fmt.Sprintf("<zip: %s>", strings.Join(files, ", "))
+}
+
+// redactJSONValue replace the values of secret keys in decoded JSON
+func redactJSONValue(val interface{}, extraKeys map[string]bool) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if redactedJSONKeys[key] || extraKeys[key] {
+				if s, ok := item.(string); ok && s == "" {
+					continue
+				}
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSONValue(item, extraKeys)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSONValue(item, extraKeys)
+		}
+	}
+	return val
+}
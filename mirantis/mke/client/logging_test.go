@@ -0,0 +1,153 @@
+package client_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestLoggingRoundTripperKeepsBodies(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodPost,
+	}
+	expectedReqBody := "myrequest"
+	expectedRespBody := "myresponse"
+
+	svr := MockTestServer(nil, MockHandlerMap{
+		mockRequest: func(w http.ResponseWriter, r *http.Request) {
+			if b, _ := ioutil.ReadAll(r.Body); string(b) != expectedReqBody {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.Write([]byte(expectedRespBody))
+		},
+	})
+
+	u, _ := url.Parse(svr.URL)
+	hc := svr.Client()
+	lrt := client.NewLoggingRoundTripper(hc.Transport)
+	lrt.LogBodies = true
+	hc.Transport = lrt
+	c, err := client.NewClient(u, nil, hc)
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte(expectedReqBody))
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	resp, err := c.ApiGeneric(ctx, req)
+	if err != nil {
+		t.Fatalf("Logged request failed: %s", err)
+	}
+	if b, _ := resp.BodyBytes(); string(b) != expectedRespBody {
+		t.Errorf("Logged request lost the response body: %s", b)
+	}
+}
+
+// roundTripperFunc a RoundTripper from a function
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestLoggingRoundTripperDoesNotModifyRequest(t *testing.T) {
+	expectedReqBody := "myrequest"
+
+	var sentReq *http.Request
+	var sentBody string
+	lrt := client.NewLoggingRoundTripper(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		sentReq = req
+		b, _ := ioutil.ReadAll(req.Body)
+		sentBody = string(b)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	}))
+
+	for _, logBodies := range []bool{true, false} {
+		lrt.LogBodies = logBodies
+
+		// a body without GetBody can only be read once
+		body := ioutil.NopCloser(strings.NewReader(expectedReqBody))
+		req, _ := http.NewRequest(http.MethodPost, "https://mke.example/mypath", nil)
+		req.Body = body
+
+		if _, err := lrt.RoundTrip(req); err != nil {
+			t.Fatalf("Logged request failed: %s", err)
+		}
+		if sentBody != expectedReqBody {
+			t.Errorf("Logged request lost the request body: %s", sentBody)
+		}
+		if req.Body != body || req.GetBody != nil {
+			t.Error("Logged request was modified")
+		}
+		// without bodies to log, the request is passed on as it is
+		if !logBodies && sentReq != req {
+			t.Error("Logged request was copied without logging its body")
+		}
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set(client.HeaderKeyAuthorization, client.BearerTokenHeaderValue("mytoken"))
+	headers.Set("Content-Type", "application/json")
+
+	redacted := client.RedactHeaders(headers)
+
+	if strings.Contains(redacted[client.HeaderKeyAuthorization], "mytoken") {
+		t.Errorf("Authorization header was not redacted: %s", redacted[client.HeaderKeyAuthorization])
+	}
+	if redacted["Content-Type"] != "application/json" {
+		t.Errorf("Other headers should not be redacted: %+v", redacted)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	login := client.NewAuthUP("myuser", "mypassword")
+	loginBody := client.RedactBody("application/json", []byte(`{"username":"myuser","password":"mypassword","token":""}`))
+	if strings.Contains(loginBody, login.Password) || !strings.Contains(loginBody, login.Username) {
+		t.Errorf("Login body was not redacted correctly: %s", loginBody)
+	}
+
+	mfaBody := client.RedactRequestBody("application/json", []byte(`{"username":"myuser","password":"mypassword","code":"123456"}`))
+	if strings.Contains(mfaBody, "123456") || strings.Contains(mfaBody, "mypassword") {
+		t.Errorf("Login MFA code was not redacted: %s", mfaBody)
+	}
+
+	errorBody := client.RedactBody("application/json", []byte(`{"errors":[{"code":"INVALID_MFA_CODE","message":"invalid code"}]}`))
+	if !strings.Contains(errorBody, client.APIErrorCodeInvalidMFACode) {
+		t.Errorf("Response error code should not be redacted: %s", errorBody)
+	}
+
+	tokenBody := client.RedactBody("application/json", client.NewLoginResponse("mytoken").Bytes())
+	if strings.Contains(tokenBody, "mytoken") {
+		t.Errorf("Login response token was not redacted: %s", tokenBody)
+	}
+
+	cert, key := MockCertificate("myuser")
+	pemBody := client.RedactBody("text/plain", []byte(cert+key))
+	if strings.Contains(pemBody, key) || !strings.Contains(pemBody, cert) {
+		t.Errorf("Private key was not redacted: %s", pemBody)
+	}
+
+	zipBody := client.RedactBody("application/zip", MockClientBundleZip(map[string]string{
+		"key.pem":  key,
+		"cert.pem": cert,
+	}))
+	if strings.Contains(zipBody, "PRIVATE KEY") || !strings.Contains(zipBody, "key.pem") {
+		t.Errorf("Client bundle zip was not redacted: %s", zipBody)
+	}
+}
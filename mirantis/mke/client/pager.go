@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+/**
+
+# Paginated collections
+
+eNZi collection endpoints (accounts, teams, members, public keys) return one
+page of items at a time, along with a nextPageStart cursor which is passed back
+as the "start" query parameter to retrieve the following page. The last page
+has an empty cursor.
+
+A Pager walks through such a collection, one page per Next call:
+
+	pager := c.NewPager(target, NewPageOptionsDefault())
+	for pager.More() {
+		var page GetKeysResponse
+		if err := pager.Next(ctx, &page); err != nil {
+			return err
+		}
+		keys = append(keys, page.AccountPubKeys...)
+	}
+*/
+
+const (
+	QueryKeyPageStart = "start"
+	QueryKeyPageLimit = "limit"
+
+	// DefaultPageSize items requested per page by default
+	DefaultPageSize = 100
+)
+
+var (
+	ErrPageCursorLoop = errors.New("MKE returned a page cursor that was already used")
+)
+
+// PageOptions how a Pager walks through a collection
+type PageOptions struct {
+	// PageSize items requested per page, 0 uses the MKE default
+	PageSize int
+	// MaxItems stop after this many items, 0 for no limit
+	MaxItems int
+}
+
+// NewPageOptionsDefault PageOptions constructor with the default settings
+func NewPageOptionsDefault() PageOptions {
+	return PageOptions{
+		PageSize: DefaultPageSize,
+	}
+}
+
+// Page a decoded page response from a collection endpoint
+type Page interface {
+	// PageLength how many items the page held
+	PageLength() int
+	// PageNextStart cursor for the next page, empty for the last page
+	PageNextStart() string
+}
+
+// Pager iterate through the pages of a collection target
+type Pager struct {
+	client *Client
+	target string
+	opts   PageOptions
+
+	start string
+	used  map[string]bool
+	count int
+	done  bool
+}
+
+// NewPager Pager constructor for a collection target
+func (c *Client) NewPager(target string, opts PageOptions) *Pager {
+	return &Pager{
+		client: c,
+		target: target,
+		opts:   opts,
+		used:   map[string]bool{},
+	}
+}
+
+// More are there more pages to retrieve
+func (p *Pager) More() bool {
+	return !p.done
+}
+
+// Next retrieve the next page of the collection, decoding it into page
+func (p *Pager) Next(ctx context.Context, page Page) error {
+	if p.done {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	req, err := p.client.RequestFromTargetAndBytesBody(ctx, http.MethodGet, p.target, []byte{})
+	if err != nil {
+		return err
+	}
+
+	query := req.URL.Query()
+	if limit := p.limit(); limit > 0 {
+		query.Set(QueryKeyPageLimit, strconv.Itoa(limit))
+	}
+	if p.start != "" {
+		query.Set(QueryKeyPageStart, p.start)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := p.client.doAuthorizedRequest(req)
+	if err != nil {
+		return err
+	}
+
+	if err := resp.JSONMarshallBody(page); err != nil {
+		return err
+	}
+
+	p.count += page.PageLength()
+
+	next := page.PageNextStart()
+	if next == "" || (p.opts.MaxItems > 0 && p.count >= p.opts.MaxItems) {
+		p.done = true
+		return nil
+	}
+	if p.used[next] {
+		p.done = true
+		return fmt.Errorf("%w; %s", ErrPageCursorLoop, next)
+	}
+
+	p.used[next] = true
+	p.start = next
+
+	return nil
+}
+
+// Trim cut a collected list length down to the MaxItems limit
+func (p *Pager) Trim(length int) int {
+	if p.opts.MaxItems > 0 && length > p.opts.MaxItems {
+		return p.opts.MaxItems
+	}
+	return length
+}
+
+// limit how many items to ask for in the next page
+func (p *Pager) limit() int {
+	limit := p.opts.PageSize
+	if p.opts.MaxItems > 0 {
+		remaining := p.opts.MaxItems - p.count
+		if limit <= 0 || remaining < limit {
+			limit = remaining
+		}
+	}
+	return limit
+}
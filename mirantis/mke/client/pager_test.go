@@ -0,0 +1,149 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+// generate a MockHandler which serves a number of public keys in pages, using
+// the index of the first key on a page as the cursor
+func mockHandlerPagedKeys(total int) MockHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get(client.QueryKeyPageStart))
+		limit, _ := strconv.Atoi(r.URL.Query().Get(client.QueryKeyPageLimit))
+		if limit == 0 {
+			limit = 2
+		}
+
+		resp := client.GetKeysResponse{}
+		for i := start; i < start+limit && i < total; i++ {
+			resp.AccountPubKeys = append(resp.AccountPubKeys, client.AccountPublicKey{ID: fmt.Sprintf("key%d", i)})
+		}
+		if start+limit < total {
+			resp.NextPageStart = strconv.Itoa(start + limit)
+		}
+
+		MockServerHandlerGeneratorReturnJson(resp)(w, r)
+	}
+}
+
+func TestPagerAllPages(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   "mycollection",
+		Method: http.MethodGet,
+	}
+
+	svr := MockTestServer(nil, MockHandlerMap{
+		mockRequest: mockHandlerPagedKeys(7),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &client.Auth{Mode: client.AuthModeClientCert}, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	keys := []client.AccountPublicKey{}
+	pages := 0
+	pager := c.NewPager(mockRequest.Path, client.PageOptions{PageSize: 3})
+	for pager.More() {
+		var page client.GetKeysResponse
+		if err := pager.Next(ctx, &page); err != nil {
+			t.Fatalf("Pager failed: %s", err)
+		}
+		keys = append(keys, page.AccountPubKeys...)
+		pages++
+	}
+
+	if len(keys) != 7 || pages != 3 {
+		t.Errorf("Pager returned %d keys in %d pages, expected 7 in 3", len(keys), pages)
+	}
+	for i, key := range keys {
+		if key.ID != fmt.Sprintf("key%d", i) {
+			t.Errorf("Pager returned keys out of order or duplicated: %+v", keys)
+			break
+		}
+	}
+}
+
+func TestPagerMaxItems(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   "mycollection",
+		Method: http.MethodGet,
+	}
+
+	svr := MockTestServer(nil, MockHandlerMap{
+		mockRequest: mockHandlerPagedKeys(20),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &client.Auth{Mode: client.AuthModeClientCert}, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	keys := []client.AccountPublicKey{}
+	pager := c.NewPager(mockRequest.Path, client.PageOptions{PageSize: 3, MaxItems: 5})
+	for pager.More() {
+		var page client.GetKeysResponse
+		if err := pager.Next(ctx, &page); err != nil {
+			t.Fatalf("Pager failed: %s", err)
+		}
+		keys = append(keys, page.AccountPubKeys...)
+	}
+
+	if len(keys[:pager.Trim(len(keys))]) != 5 {
+		t.Errorf("Pager did not stop at the max items: %d", len(keys))
+	}
+}
+
+func TestPagerCursorLoop(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   "mycollection",
+		Method: http.MethodGet,
+	}
+
+	svr := MockTestServer(nil, MockHandlerMap{
+		mockRequest: MockServerHandlerGeneratorReturnJson(client.GetKeysResponse{
+			AccountPubKeys: []client.AccountPublicKey{{ID: "key"}},
+			NextPageStart:  "always",
+		}),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &client.Auth{Mode: client.AuthModeClientCert}, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	var err2 error
+	pages := 0
+	pager := c.NewPager(mockRequest.Path, client.NewPageOptionsDefault())
+	for pager.More() && pages < 10 {
+		var page client.GetKeysResponse
+		if err2 = pager.Next(ctx, &page); err2 != nil {
+			break
+		}
+		pages++
+	}
+
+	if !errors.Is(err2, client.ErrPageCursorLoop) {
+		t.Errorf("Pager did not detect a cursor loop: %s (%d pages)", err2, pages)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := c.NewPager(mockRequest.Path, client.NewPageOptionsDefault()).Next(cancelled, &client.GetKeysResponse{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Pager did not stop for a cancelled context: %s", err)
+	}
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+/**
+
+# Retrying transient failures
+
+MKE managers return 502/503/504 responses while a leader election or upgrade is
+in progress, and connections can drop when a manager restarts. Requests that
+fail in this way are retried according to the client RetryPolicy, using an
+exponential backoff with jitter, or the delay that MKE asked for in a
+Retry-After header.
+
+Requests which are not idempotent (POST, PATCH) are not retried unless the
+policy allows it, as the first attempt may have been applied. Requests which
+are known to have no side effects, such as logging in, are marked as retryable
+through their context.
+*/
+
+const (
+	// DefaultRetryMaxAttempts total attempts made for a request by default
+	DefaultRetryMaxAttempts = 4
+	// DefaultRetryMinBackoff delay before the first retry by default
+	DefaultRetryMinBackoff = time.Second
+	// DefaultRetryMaxBackoff longest delay between retries by default
+	DefaultRetryMaxBackoff = 30 * time.Second
+
+	HeaderKeyRetryAfter = "Retry-After"
+)
+
+var (
+	// DefaultRetryableStatusCodes http status codes that MKE returns for transient failures
+	DefaultRetryableStatusCodes = []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+)
+
+// retryableContextKey context key marking requests as safe (or unsafe) to retry
+type retryableContextKey struct{}
+
+// withRetryable mark the requests made with a context as safe to retry whatever
+// their method, because they have no side effects
+func withRetryable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableContextKey{}, true)
+}
+
+// withoutRetry mark the requests made with a context as never to be retried,
+// whatever the RetryPolicy, because they can't be sent twice
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableContextKey{}, false)
+}
+
+// requestRetryable was the request marked as safe to retry, and was it marked at all
+func requestRetryable(req *http.Request) (retryable bool, marked bool) {
+	retryable, marked = req.Context().Value(retryableContextKey{}).(bool)
+	return retryable, marked
+}
+
+// RetryPolicy how the client retries requests that failed for transient reasons
+type RetryPolicy struct {
+	// MaxAttempts total attempts for a request, 1 or less disables retries
+	MaxAttempts int
+	// MinBackoff delay before the first retry, doubled for each following retry
+	MinBackoff time.Duration
+	// MaxBackoff longest delay between retries, including Retry-After delays
+	MaxBackoff time.Duration
+	// RetryableStatusCodes response status codes which are retried
+	RetryableStatusCodes []int
+	// RetryNonIdempotent also retry requests such as POST which may have been applied
+	RetryNonIdempotent bool
+}
+
+// NewRetryPolicyDefault RetryPolicy constructor with the default settings
+func NewRetryPolicyDefault() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          DefaultRetryMaxAttempts,
+		MinBackoff:           DefaultRetryMinBackoff,
+		MaxBackoff:           DefaultRetryMaxBackoff,
+		RetryableStatusCodes: DefaultRetryableStatusCodes,
+	}
+}
+
+// NewRetryPolicyNone RetryPolicy constructor which never retries
+func NewRetryPolicyNone() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 1,
+	}
+}
+
+// Backoff how long to wait before a retry, for the number of attempts already made
+// The delay grows exponentially, with jitter so that parallel operations spread out.
+func (rp RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := rp.MinBackoff
+	for i := 1; i < attempt && backoff < rp.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if rp.MaxBackoff > 0 && backoff > rp.MaxBackoff {
+		backoff = rp.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// retryDelay decide if a request attempt should be retried, and how long to wait
+func (rp RetryPolicy) retryDelay(req *http.Request, res *Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= rp.MaxAttempts {
+		return 0, false
+	}
+	retryable, marked := requestRetryable(req)
+	if marked && !retryable {
+		return 0, false
+	}
+	if !rp.RetryNonIdempotent && !isIdempotentMethod(req.Method) && !retryable {
+		return 0, false
+	}
+
+	if res == nil {
+		// a transport error, retry unless we gave up on purpose or it won't go away
+		if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || isCertificateError(err) {
+			return 0, false
+		}
+		return rp.Backoff(attempt), true
+	}
+
+	if !rp.retryableStatusCode(res.StatusCode) {
+		return 0, false
+	}
+
+	if delay, ok := retryAfterDelay(res.Header.Get(HeaderKeyRetryAfter)); ok {
+		if rp.MaxBackoff > 0 && delay > rp.MaxBackoff {
+			delay = rp.MaxBackoff
+		}
+		return delay, true
+	}
+	return rp.Backoff(attempt), true
+}
+
+func (rp RetryPolicy) retryableStatusCode(status int) bool {
+	for _, code := range rp.RetryableStatusCodes {
+		if code == status {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdempotentMethod can a request with this method safely be sent more than once
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isCertificateError did a transport error happen because the server certificate was not trusted
+func isCertificateError(err error) bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+
+	return errors.As(err, &unknownAuthority) || errors.As(err, &invalid) || errors.As(err, &hostname)
+}
+
+// retryAfterDelay interpret a Retry-After header, which is either seconds or an http date
+func retryAfterDelay(val string) (time.Duration, bool) {
+	if val == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(val); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(val); err == nil {
+		delay := time.Until(at)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// waitForRetry sleep for a retry delay, unless the context is done first
+func waitForRetry(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,256 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+// generate a MockHandler which fails with a status a number of times before succeeding
+func mockHandlerFailTimes(status, failures int, calls *int) MockHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		if *calls <= failures {
+			w.WriteHeader(status)
+		}
+	}
+}
+
+func TestRetryTransientFailure(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodGet,
+	}
+	calls := 0
+
+	svr := MockTestServer(nil, MockHandlerMap{
+		mockRequest: mockHandlerFailTimes(http.StatusServiceUnavailable, 2, &calls),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, nil, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+	c.RetryPolicy.MinBackoff = time.Millisecond
+	c.RetryPolicy.MaxBackoff = 5 * time.Millisecond
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte{})
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	if _, err := c.ApiGeneric(ctx, req); err != nil {
+		t.Errorf("Request was not retried after a transient failure: %s", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodGet,
+	}
+	calls := 0
+
+	svr := MockTestServer(nil, MockHandlerMap{
+		mockRequest: mockHandlerFailTimes(http.StatusBadGateway, 10, &calls),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, nil, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+	c.RetryPolicy.MaxAttempts = 2
+	c.RetryPolicy.MinBackoff = time.Millisecond
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte{})
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	if _, err := c.ApiGeneric(ctx, req); err == nil {
+		t.Error("Request succeeded when all attempts failed")
+	} else if !errors.Is(err, client.ErrResponseError) {
+		t.Errorf("Wrong error received after retries: %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestRetryNonIdempotentNotRetried(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   "mypath",
+		Method: http.MethodPost,
+	}
+	calls := 0
+
+	svr := MockTestServer(nil, MockHandlerMap{
+		mockRequest: mockHandlerFailTimes(http.StatusServiceUnavailable, 1, &calls),
+	})
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, nil, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+	c.RetryPolicy.MinBackoff = time.Millisecond
+
+	req, err := c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte("mybody"))
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	if _, err := c.ApiGeneric(ctx, req); err == nil {
+		t.Error("POST request was retried by default")
+	}
+
+	c.RetryPolicy.RetryNonIdempotent = true
+	calls = 0
+
+	req, err = c.RequestFromTargetAndBytesBody(ctx, mockRequest.Method, mockRequest.Path, []byte("mybody"))
+	if err != nil {
+		t.Fatalf("Could not make a request: %s", err)
+	}
+
+	if _, err := c.ApiGeneric(ctx, req); err != nil {
+		t.Errorf("POST request was not retried when allowed: %s", err)
+	}
+}
+
+func TestRetryLoginTransientFailure(t *testing.T) {
+	ctx := context.Background()
+	srvAuth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	clAuth := client.NewAuthUP(srvAuth.Username, srvAuth.Password)
+	logins := 0
+
+	// the first login hits a manager which is restarting
+	handler := MockTestHandler(&srvAuth, MockHandlerMap{})
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, client.URLTargetForAuth) {
+			logins++
+			if logins == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer svr.Close()
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, &clAuth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+	c.RetryPolicy.MinBackoff = time.Millisecond
+
+	if err := c.ApiLogin(ctx); err != nil {
+		t.Errorf("Login was not retried after a transient failure: %s", err)
+	}
+	if logins != 2 {
+		t.Errorf("Expected 2 login attempts, got %d", logins)
+	}
+}
+
+func TestRetryLoginTwoFactorCode(t *testing.T) {
+	ctx := context.Background()
+	srvAuth := client.Auth{
+		Username: "myuser",
+		Password: "mypassword",
+		Token:    "mytoken",
+	}
+	logins := 0
+	codes := []string{}
+
+	// the first login of each client hits a manager which is restarting
+	handler := MockTestHandler(&srvAuth, MockHandlerMap{})
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, client.URLTargetForAuth) {
+			logins++
+			var login client.Auth
+			json.NewDecoder(r.Body).Decode(&login)
+			codes = append(codes, login.Code)
+			if logins == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write(client.NewLoginResponse(srvAuth.Token).Bytes())
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer svr.Close()
+	u, _ := url.Parse(svr.URL)
+
+	// a static code may have been used up, so it is not sent again
+	clAuth := client.NewAuthUP(srvAuth.Username, srvAuth.Password)
+	clAuth.Code = "123456"
+	c, err := client.NewClient(u, &clAuth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+	c.RetryPolicy.MinBackoff = time.Millisecond
+	c.RetryPolicy.RetryNonIdempotent = true
+
+	if err := c.ApiLogin(ctx); err == nil {
+		t.Error("Login with a static two factor code was retried")
+	}
+	if logins != 1 {
+		t.Errorf("Expected 1 login attempt with a static code, got %d", logins)
+	}
+
+	// a TOTP secret gives every attempt a code of its own
+	logins = 0
+	codes = []string{}
+	clAuthTOTP := client.NewAuthUP(srvAuth.Username, srvAuth.Password)
+	clAuthTOTP.TOTPSecret = "JBSWY3DPEHPK3PXP"
+	c, err = client.NewClient(u, &clAuthTOTP, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+	c.RetryPolicy.MinBackoff = time.Millisecond
+
+	if err := c.ApiLogin(ctx); err != nil {
+		t.Errorf("Login with a TOTP secret was not retried: %s", err)
+	}
+	if len(codes) != 2 || codes[0] == "" || codes[1] == "" {
+		t.Errorf("Expected 2 login attempts each with a TOTP code, got %+v", codes)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	rp := client.RetryPolicy{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: time.Second,
+	}
+
+	if d := rp.Backoff(1); d < 50*time.Millisecond || d > 100*time.Millisecond {
+		t.Errorf("First backoff out of range: %s", d)
+	}
+	if d := rp.Backoff(3); d < 200*time.Millisecond || d > 400*time.Millisecond {
+		t.Errorf("Third backoff out of range: %s", d)
+	}
+	if d := rp.Backoff(20); d > time.Second {
+		t.Errorf("Backoff was not capped: %s", d)
+	}
+}
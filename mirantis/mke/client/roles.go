@@ -0,0 +1,113 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+)
+
+/**
+Role abstractions
+
+Roles are named sets of the operations which a grant allows on a collection.
+Operations are grouped by the kind of object that they act on, and their names
+are unique across groups, such as "Container Create".
+
+@TODO only the swarm operation sets are modelled in KnownRoleOperations, so
+	roles can't be created with kubernetes operations yet. Kubernetes operations
+	of roles made elsewhere are still read, by name.
+
+@see https://docs.mirantis.com/mke/3.5/ops/authorize-rolebased-access/define-roles-with-authorized-api-operations.html
+*/
+
+// RoleOperations the operations allowed by a role, by group
+// Each operation maps to a list of its arguments, which MKE leaves empty.
+type RoleOperations map[string]map[string][]string
+
+// Role api interpretation of an RBAC role
+type Role struct {
+	ID         string         `json:"id"          description:"the ID of the role"`
+	Name       string         `json:"name"        description:"the name of the role"`
+	SystemRole bool           `json:"system_role" description:"whether the role is built into MKE, and cannot be changed"`
+	Operations RoleOperations `json:"operations"  description:"the operations allowed by the role"`
+}
+
+// KnownRoleOperations the operations that MKE roles can allow, by group
+var KnownRoleOperations = map[string][]string{
+	"Config": {
+		"Config Create", "Config Delete", "Config Update", "Config Use", "Config View",
+	},
+	"Container": {
+		"Container Attach", "Container Changes", "Container Connect", "Container Create",
+		"Container Delete", "Container Disconnect", "Container Exec", "Container Export",
+		"Container Logs", "Container Pause", "Container Rename", "Container Resize",
+		"Container Restart", "Container Start", "Container Stats", "Container Stop",
+		"Container Top", "Container Unpause", "Container Update", "Container View",
+	},
+	"Image": {
+		"Image Build", "Image Commit", "Image Delete", "Image History", "Image Import",
+		"Image Load", "Image Pull", "Image Push", "Image Tag", "Image View",
+	},
+	"Network": {
+		"Network Attach", "Network Connect", "Network Create", "Network Delete",
+		"Network Disconnect", "Network View",
+	},
+	"Node": {
+		"Node Schedule", "Node Update", "Node View",
+	},
+	"Secret": {
+		"Secret Create", "Secret Delete", "Secret Update", "Secret Use", "Secret View",
+	},
+	"Service": {
+		"Service Create", "Service Delete", "Service Logs", "Service Update",
+		"Service View", "Service View Tasks",
+	},
+	"Volume": {
+		"Volume Attach", "Volume Create", "Volume Delete", "Volume View",
+	},
+}
+
+// KnownRoleOperationNames all of the known operations, sorted
+func KnownRoleOperationNames() []string {
+	names := []string{}
+	for _, ops := range KnownRoleOperations {
+		names = append(names, ops...)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewRoleOperations RoleOperations constructor from operation names, which are
+// grouped using the known operations
+func NewRoleOperations(names []string) (RoleOperations, error) {
+	groupOf := map[string]string{}
+	for group, ops := range KnownRoleOperations {
+		for _, op := range ops {
+			groupOf[op] = group
+		}
+	}
+
+	ro := RoleOperations{}
+	for _, name := range names {
+		group, ok := groupOf[name]
+		if !ok {
+			return ro, fmt.Errorf("%w; %s", ErrUnknownOperation, name)
+		}
+		if _, ok := ro[group]; !ok {
+			ro[group] = map[string][]string{}
+		}
+		ro[group][name] = []string{}
+	}
+	return ro, nil
+}
+
+// Names the names of all of the operations, sorted
+func (ro RoleOperations) Names() []string {
+	names := []string{}
+	for _, ops := range ro {
+		for name := range ops {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,42 @@
+package client_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestNewRoleOperations(t *testing.T) {
+	ro, err := client.NewRoleOperations([]string{"Container View", "Container Logs", "Service View"})
+	if err != nil {
+		t.Fatalf("Could not group known operations: %s", err)
+	}
+
+	if len(ro) != 2 || len(ro["Container"]) != 2 || len(ro["Service"]) != 1 {
+		t.Errorf("Operations were not grouped: %+v", ro)
+	}
+	if names := ro.Names(); !reflect.DeepEqual(names, []string{"Container Logs", "Container View", "Service View"}) {
+		t.Errorf("Wrong operation names: %+v", names)
+	}
+
+	if _, err := client.NewRoleOperations([]string{"Container Teleport"}); !errors.Is(err, client.ErrUnknownOperation) {
+		t.Errorf("Wrong error for an unknown operation: %s", err)
+	}
+}
+
+func TestKnownRoleOperationNames(t *testing.T) {
+	names := client.KnownRoleOperationNames()
+
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("Operation is in more than one group: %s", name)
+		}
+		seen[name] = true
+	}
+	if !seen["Secret Use"] || !seen["Volume Attach"] {
+		t.Errorf("Known operations are missing: %+v", names)
+	}
+}
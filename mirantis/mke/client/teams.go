@@ -0,0 +1,77 @@
+package client
+
+/**
+Team and membership abstractions
+
+Teams belong to an organization, and hold members which are user accounts.
+Organizations also have members directly, which have the same form.
+
+@NOTE this should be implemented using a direct import of enzi client code
+	@see https://github.com/Mirantis/orca/blob/master/enzi/api/forms/teams.go
+	@see https://github.com/Mirantis/orca/blob/master/enzi/api/responses/responses.go
+*/
+
+// Team api interpretation of a team in an organization
+type Team struct {
+	ID           string `json:"id"           description:"the ID of the team"`
+	OrgID        string `json:"orgID"        description:"the ID of the organization the team belongs to"`
+	Name         string `json:"name"         description:"the name of the team"`
+	Description  string `json:"description"  description:"a description of the team"`
+	MembersCount int    `json:"membersCount" description:"the number of members of the team"`
+}
+
+// CreateTeam form to create a team
+type CreateTeam struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// UpdateTeam form to change a team, only the fields which are set are changed
+type UpdateTeam struct {
+	Description *string `json:"description,omitempty"`
+}
+
+// GetTeamsResponse a page of teams
+type GetTeamsResponse struct {
+	Teams         []Team `json:"teams"`
+	NextPageStart string `json:"nextPageStart"`
+}
+
+// PageLength for the Page interface
+func (gtr GetTeamsResponse) PageLength() int {
+	return len(gtr.Teams)
+}
+
+// PageNextStart for the Page interface
+func (gtr GetTeamsResponse) PageNextStart() string {
+	return gtr.NextPageStart
+}
+
+// Member api interpretation of a member of a team or organization
+type Member struct {
+	Member   Account `json:"member"   description:"the member account"`
+	IsAdmin  bool    `json:"isAdmin"  description:"whether the member is an admin of the team or organization"`
+	IsPublic bool    `json:"isPublic" description:"whether the membership is visible to non members"`
+}
+
+// SetMembership form to add a member, or change a membership
+type SetMembership struct {
+	IsAdmin  bool `json:"isAdmin"`
+	IsPublic bool `json:"isPublic"`
+}
+
+// GetMembersResponse a page of members
+type GetMembersResponse struct {
+	Members       []Member `json:"members"`
+	NextPageStart string   `json:"nextPageStart"`
+}
+
+// PageLength for the Page interface
+func (gmr GetMembersResponse) PageLength() int {
+	return len(gmr.Members)
+}
+
+// PageNextStart for the Page interface
+func (gmr GetMembersResponse) PageNextStart() string {
+	return gmr.NextPageStart
+}
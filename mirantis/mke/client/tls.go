@@ -0,0 +1,84 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+/**
+
+# TLS configuration
+
+MKE clusters usually serve their API using a certificate signed by the cluster
+internal CA, which is the ca.pem distributed in client bundles. TLSOptions lets
+a client trust that CA, rather than having to skip verification.
+*/
+
+var (
+	ErrInvalidTLSOptions = errors.New("invalid TLS options for MKE client")
+)
+
+// TLSOptions configuration for the TLS connection to MKE
+type TLSOptions struct {
+	// CACertPEM PEM encoded CA certificates to trust, in addition to the system trust store
+	CACertPEM string
+	// ServerName override the name used to verify the MKE server certificate
+	ServerName string
+	// MinVersion minimum TLS version, e.g. tls.VersionTLS12
+	MinVersion uint16
+	// ClientCertPEM PEM encoded client certificate, presented to MKE with ClientKeyPEM
+	ClientCertPEM string
+	// ClientKeyPEM PEM encoded private key for ClientCertPEM
+	ClientKeyPEM string
+	// InsecureSkipVerify do not verify the MKE server certificate at all
+	InsecureSkipVerify bool
+}
+
+// TLSConfig build a tls.Config from the options
+func (o TLSOptions) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         o.ServerName,
+		MinVersion:         o.MinVersion,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(o.CACertPEM)) {
+			return nil, fmt.Errorf("%w; no CA certificates could be read from the CA PEM", ErrInvalidTLSOptions)
+		}
+		config.RootCAs = pool
+	}
+
+	if o.ClientCertPEM != "" || o.ClientKeyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(o.ClientCertPEM), []byte(o.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("%w; client certificate: %s", ErrInvalidTLSOptions, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// TLSVersionFromString convert a version string such as "1.2" to a tls version
+func TLSVersionFromString(version string) (uint16, error) {
+	switch version {
+	case "":
+		return 0, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("%w; unknown TLS version %q", ErrInvalidTLSOptions, version)
+}
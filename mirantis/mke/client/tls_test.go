@@ -0,0 +1,85 @@
+package client_test
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestTLSOptionsCustomCA(t *testing.T) {
+	ctx := context.Background()
+	mockRequest := MockHandlerKey{
+		Path:   client.URLTargetForPing,
+		Method: http.MethodGet,
+	}
+
+	svr := httptest.NewTLSServer(MockTestHandler(nil, MockHandlerMap{
+		mockRequest: MockServerHandlerGeneratorReturnResponseStatus(http.StatusOK),
+	}))
+	defer svr.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw})
+
+	tlsConfig, err := client.TLSOptions{
+		CACertPEM:  string(caPEM),
+		ServerName: "example.com",
+		MinVersion: tls.VersionTLS12,
+	}.TLSConfig()
+	if err != nil {
+		t.Fatalf("Could not build TLS config: %s", err)
+	}
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, nil, &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}})
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	if err := c.ApiPing(ctx); err != nil {
+		t.Errorf("Client did not trust the custom CA: %s", err)
+	}
+}
+
+func TestTLSOptionsUntrusted(t *testing.T) {
+	ctx := context.Background()
+
+	svr := httptest.NewTLSServer(MockTestHandler(nil, MockHandlerMap{}))
+	defer svr.Close()
+
+	tlsConfig, err := client.TLSOptions{}.TLSConfig()
+	if err != nil {
+		t.Fatalf("Could not build TLS config: %s", err)
+	}
+
+	u, _ := url.Parse(svr.URL)
+	c, err := client.NewClient(u, nil, &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}})
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+
+	if err := c.ApiPing(ctx); err == nil {
+		t.Error("Client trusted a server certificate from an unknown CA")
+	}
+}
+
+func TestTLSOptionsInvalid(t *testing.T) {
+	if _, err := (client.TLSOptions{CACertPEM: "not a pem"}).TLSConfig(); !errors.Is(err, client.ErrInvalidTLSOptions) {
+		t.Errorf("Bad CA PEM did not produce the expected error: %s", err)
+	}
+	if _, err := (client.TLSOptions{ClientCertPEM: "not a pem"}).TLSConfig(); !errors.Is(err, client.ErrInvalidTLSOptions) {
+		t.Errorf("Client cert without a key did not produce the expected error: %s", err)
+	}
+	if _, err := client.TLSVersionFromString("1.4"); !errors.Is(err, client.ErrInvalidTLSOptions) {
+		t.Errorf("Unknown TLS version did not produce the expected error: %s", err)
+	}
+	if v, err := client.TLSVersionFromString("1.3"); err != nil || v != tls.VersionTLS13 {
+		t.Errorf("TLS version 1.3 was not converted: %d %s", v, err)
+	}
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/**
+
+# Two factor authentication
+
+MKE accounts with two factor authentication enabled need a one-time code in
+the auth/login request. The code can be provided directly (Auth.Code), which
+only works for a single login, or generated from the account TOTP secret
+(Auth.TOTPSecret) each time the client logs in.
+
+Codes are generated according to RFC 6238 with the settings used by
+authenticator apps: HMAC-SHA1, 6 digits, 30 second periods.
+*/
+
+const (
+	TOTPPeriod = 30 * time.Second
+	TOTPDigits = 6
+)
+
+var (
+	ErrInvalidTOTPSecret = errors.New("invalid TOTP secret")
+)
+
+// TOTPCode generate the time based one time code for a base32 secret at a time
+func TOTPCode(secret string, at time.Time) (string, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(secret), " ", ""))
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return "", fmt.Errorf("%w; %s", ErrInvalidTOTPSecret, err)
+	}
+	if len(key) == 0 {
+		return "", fmt.Errorf("%w; empty secret", ErrInvalidTOTPSecret)
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(at.Unix()/int64(TOTPPeriod/time.Second)))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", TOTPDigits, value%mod), nil
+}
@@ -0,0 +1,34 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B test secret "12345678901234567890", truncated to 6 digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	expected := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for ts, code := range expected {
+		got, err := client.TOTPCode(secret, time.Unix(ts, 0))
+		if err != nil {
+			t.Fatalf("TOTP code generation failed: %s", err)
+		}
+		if got != code {
+			t.Errorf("Wrong TOTP code at %d: %s != %s", ts, got, code)
+		}
+	}
+
+	if _, err := client.TOTPCode("not base32!", time.Now()); !errors.Is(err, client.ErrInvalidTOTPSecret) {
+		t.Errorf("Invalid secret did not produce the expected error: %s", err)
+	}
+}
@@ -0,0 +1,59 @@
+package connect
+
+import (
+	"context"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DataSourceOrganization for looking up an existing MKE organization by name
+func DataSourceOrganization() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceOrganizationRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Description: "Name of the organization.",
+				Required:    true,
+			},
+
+			"full_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the organization in MKE.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrganizationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	org, err := c.ApiOrgRetrieve(ctx, d.Get("name").(string))
+	if err != nil {
+		diags = append(diags, diag.Errorf("MKE Client could not find the organization: %s", err)...)
+		return diags
+	}
+
+	if org.ID == "" {
+		d.SetId(d.Get("name").(string))
+	} else {
+		d.SetId(org.ID)
+	}
+
+	diags = append(diags, resourceOrganizationSet(d, org)...)
+
+	return diags
+}
@@ -0,0 +1,9 @@
+package connect
+
+// The provider configuration helpers are exported to the connect_test package,
+// so that the provider attributes can be tested without an MKE to connect to.
+var (
+	ProviderAuth        = providerAuth
+	ProviderTLSOptions  = providerTLSOptions
+	ProviderRetryPolicy = providerRetryPolicy
+)
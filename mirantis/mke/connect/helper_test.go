@@ -0,0 +1,183 @@
+package connect_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+/**
+Here we define a mock MKE API which resources can be tested against
+
+Handlers are keyed on "METHOD path", with the path relative to the API root.
+The client authenticates as mockUsername with a pre-issued token, which the
+server checks, and does not retry failed requests.
+*/
+
+const (
+	mockUsername = "myuser"
+	mockToken    = "mytoken"
+)
+
+type mockHandlerMap map[string]http.HandlerFunc
+
+// mockClient a client for a mock MKE API serving the handlers
+func mockClient(t *testing.T, handlers mockHandlerMap) (*client.Client, *httptest.Server) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(client.HeaderKeyAuthorization) != client.BearerTokenHeaderValue(mockToken) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		handler, ok := handlers[r.Method+" "+strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(svr.Close)
+
+	u, _ := url.Parse(svr.URL)
+	auth := client.NewAuthToken(mockToken)
+	auth.Username = mockUsername
+	c, err := client.NewClient(u, &auth, svr.Client())
+	if err != nil {
+		t.Fatalf("Could not make a client: %s", err)
+	}
+	c.RetryPolicy = client.NewRetryPolicyNone()
+
+	return c, svr
+}
+
+// mockReturnJSON a handler which returns a JSON serialized value
+func mockReturnJSON(val interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, _ := json.Marshal(val)
+		w.Write(b)
+	}
+}
+
+// mockReturnStatus a handler which only sets the http status
+func mockReturnStatus(status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}
+}
+
+// mockObjectHandlers a mock MKE API holding a single object, which is a pointer
+// to a struct whose zero value means that it doesn't exist
+// The object is made by a createMethod request to createTarget, then retrieved
+// (GET), updated (PATCH) and deleted (DELETE) at target. create and update
+// apply a request to the object, and return false to reject it as a bad
+// request; without an update function PATCH is not handled.
+func mockObjectHandlers(obj interface{}, createMethod, createTarget, target string, create, update func(r *http.Request) bool) mockHandlerMap {
+	objVal := reflect.ValueOf(obj).Elem()
+
+	apply := func(change func(r *http.Request) bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !change(r) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mockReturnJSON(obj)(w, r)
+		}
+	}
+
+	handlers := mockHandlerMap{
+		createMethod + " " + createTarget: apply(create),
+		http.MethodGet + " " + target: func(w http.ResponseWriter, r *http.Request) {
+			if objVal.IsZero() {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			mockReturnJSON(obj)(w, r)
+		},
+		http.MethodDelete + " " + target: func(w http.ResponseWriter, r *http.Request) {
+			objVal.Set(reflect.Zero(objVal.Type()))
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}
+	if update != nil {
+		handlers[http.MethodPatch+" "+target] = apply(update)
+	}
+	return handlers
+}
+
+// testMockLifecycle test a resource against a mock MKE API: it is created
+// from the config with the expected ID, a change made in MKE by drift is picked
+// up by a refresh and checked by check, and once it is deleted a refresh of the
+// ID removes it from state.
+func testMockLifecycle(t *testing.T, c *client.Client, r *schema.Resource, config map[string]interface{}, id string, drift func(), check func(d *schema.ResourceData)) {
+	ctx := context.Background()
+	d := schema.TestResourceDataRaw(t, r.Schema, config)
+
+	if diags := r.CreateContext(ctx, d, c); diags.HasError() {
+		t.Fatalf("Create failed: %+v", diags)
+	}
+	if d.Id() != id {
+		t.Errorf("Create set the wrong ID: %s != %s", d.Id(), id)
+	}
+
+	drift()
+
+	if diags := r.ReadContext(ctx, d, c); diags.HasError() {
+		t.Fatalf("Read failed: %+v", diags)
+	}
+	if d.Id() != id {
+		t.Fatalf("Read removed the resource from state: %+v", d.State())
+	}
+	check(d)
+
+	if diags := r.DeleteContext(ctx, d, c); diags.HasError() {
+		t.Fatalf("Delete failed: %+v", diags)
+	}
+
+	d = r.TestResourceData()
+	d.SetId(id)
+	if diags := r.ReadContext(ctx, d, c); diags.HasError() {
+		t.Fatalf("Read of a deleted resource failed: %+v", diags)
+	}
+	if d.Id() != "" {
+		t.Error("Read did not remove a deleted resource from state")
+	}
+}
+
+// mockCertificate a self signed certificate PEM for a common name
+func mockCertificate(t *testing.T, commonName string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Could not generate a key: %s", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Could not create a certificate: %s", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+}
@@ -0,0 +1,39 @@
+package connect
+
+import (
+	"errors"
+	"strings"
+)
+
+/**
+Resources which are nested inside an MKE account, such as public keys and
+teams, use an ID made of the account and their own name or ID, joined with a
+slash: "account/keyID". This is also the format used to import them.
+*/
+
+const (
+	idSeparator = "/"
+)
+
+var (
+	ErrInvalidID = errors.New("resource ID is not in the expected format")
+)
+
+// joinID build a resource ID from its parts
+func joinID(parts ...string) string {
+	return strings.Join(parts, idSeparator)
+}
+
+// splitID split a resource ID into an expected number of non empty parts
+func splitID(id string, count int) ([]string, bool) {
+	parts := strings.Split(id, idSeparator)
+	if len(parts) != count {
+		return nil, false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, false
+		}
+	}
+	return parts, true
+}
@@ -2,13 +2,29 @@ package connect
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
 )
 
+var (
+	ErrNoProviderAuth = errors.New("no MKE authentication configured; set username and password, a token, or a client bundle")
+	// ErrNoProviderTokenUsername a token can't tell us which account it belongs to
+	ErrNoProviderTokenUsername = errors.New("MKE token authentication needs the username of the account that the token belongs to")
+)
+
 func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
@@ -23,10 +39,49 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("MKE_USER", nil),
 			},
 			"password": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Sensitive:   true,
-				DefaultFunc: schema.EnvDefaultFunc("MKE_PASS", nil),
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_PASS", nil),
+				ConflictsWith: []string{"client_bundle", "token", "token_file"},
+			},
+			"mfa_code": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "A one-time two factor code for logging in with a password. A code can only be used once, so prefer totp_secret for long runs.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_MFA_CODE", nil),
+				ConflictsWith: []string{"totp_secret"},
+			},
+			"totp_secret": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "The base32 TOTP secret of the account, used to generate a two factor code for each login.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_TOTP_SECRET", nil),
+				ConflictsWith: []string{"mfa_code"},
+			},
+			"token": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "A pre-issued MKE auth token, used instead of logging in with a password.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_TOKEN", nil),
+				ConflictsWith: []string{"password", "client_bundle", "token_file"},
+			},
+			"token_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a file containing a pre-issued MKE auth token. The file is read again whenever it changes.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_TOKEN_FILE", nil),
+				ConflictsWith: []string{"password", "client_bundle", "token"},
+			},
+			"client_bundle": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a client bundle zip, or a directory containing an extracted client bundle, used to authenticate with mutual TLS instead of a password.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_CLIENT_BUNDLE", nil),
+				ConflictsWith: []string{"password", "token", "token_file"},
 			},
 			"unsafe_ssl_client": {
 				Type:        schema.TypeBool,
@@ -34,9 +89,117 @@ func Provider() *schema.Provider {
 				Default:     false,
 				DefaultFunc: schema.EnvDefaultFunc("MKE_UNSAFE_CLIENT", nil),
 			},
+			"ca_cert": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "PEM encoded CA certificate(s) to trust for the MKE endpoint, such as the ca.pem from a client bundle.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_CA_CERT", nil),
+				ConflictsWith: []string{"ca_cert_file"},
+			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a PEM file of CA certificate(s) to trust for the MKE endpoint.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_CA_CERT_FILE", nil),
+				ConflictsWith: []string{"ca_cert"},
+			},
+			"tls_server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Server name used to verify the MKE endpoint certificate, if it differs from the endpoint host.",
+				DefaultFunc: schema.EnvDefaultFunc("MKE_TLS_SERVER_NAME", nil),
+			},
+			"tls_min_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Minimum TLS version used to connect to MKE: 1.0, 1.1, 1.2 or 1.3",
+				DefaultFunc:  schema.EnvDefaultFunc("MKE_TLS_MIN_VERSION", "1.2"),
+				ValidateFunc: validation.StringInSlice([]string{"1.0", "1.1", "1.2", "1.3"}, false),
+			},
+			"client_cert": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "PEM encoded client certificate presented to MKE.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_CLIENT_CERT", nil),
+				ConflictsWith: []string{"client_cert_file"},
+			},
+			"client_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a PEM encoded client certificate presented to MKE.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_CLIENT_CERT_FILE", nil),
+				ConflictsWith: []string{"client_cert"},
+			},
+			"client_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "PEM encoded private key for the client certificate.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_CLIENT_KEY", nil),
+				ConflictsWith: []string{"client_key_file"},
+			},
+			"client_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a PEM encoded private key for the client certificate.",
+				DefaultFunc:   schema.EnvDefaultFunc("MKE_CLIENT_KEY_FILE", nil),
+				ConflictsWith: []string{"client_key"},
+			},
+			"token_lifetime": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "How long an MKE auth token is trusted before it is renewed, as a duration (e.g. 60m). Set to 0 to only renew when MKE rejects the token.",
+				DefaultFunc:      schema.EnvDefaultFunc("MKE_TOKEN_LIFETIME", client.DefaultTokenLifetime.String()),
+				ValidateDiagFunc: validateDuration,
+			},
+			"retry_max_attempts": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Total attempts made for an MKE API request which fails for a transient reason. Set to 1 to disable retries.",
+				DefaultFunc: schema.EnvDefaultFunc("MKE_RETRY_MAX_ATTEMPTS", client.DefaultRetryMaxAttempts),
+			},
+			"retry_min_backoff": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Delay before the first retry of a failed MKE API request, doubled for each following retry.",
+				DefaultFunc:      schema.EnvDefaultFunc("MKE_RETRY_MIN_BACKOFF", client.DefaultRetryMinBackoff.String()),
+				ValidateDiagFunc: validateDuration,
+			},
+			"retry_max_backoff": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Longest delay between retries of a failed MKE API request.",
+				DefaultFunc:      schema.EnvDefaultFunc("MKE_RETRY_MAX_BACKOFF", client.DefaultRetryMaxBackoff.String()),
+				ValidateDiagFunc: validateDuration,
+			},
+			"retry_status_codes": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "HTTP status codes which are retried. Defaults to 429, 502, 503 and 504.",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"retry_non_idempotent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Also retry requests such as POST, which may have been applied by MKE before it failed.",
+				DefaultFunc: schema.EnvDefaultFunc("MKE_RETRY_NON_IDEMPOTENT", false),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"mke_clientbundle": ResourceClientBundle(),
+			"mke_clientbundle":       ResourceClientBundle(),
+			"mke_account_public_key": ResourceAccountPublicKey(),
+			"mke_user":               ResourceUser(),
+			"mke_organization":       ResourceOrganization(),
+			"mke_team":               ResourceTeam(),
+			"mke_team_members":       ResourceTeamMembers(),
+			"mke_team_member":        ResourceTeamMember(),
+			"mke_org_member":         ResourceOrgMember(),
+			"mke_role":               ResourceRole(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"mke_organization": DataSourceOrganization(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -47,39 +210,95 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	var diags diag.Diagnostics
 
 	endpoint := d.Get("endpoint").(string)
-	username := d.Get("username").(string)
-	password := d.Get("password").(string)
 	unsafeClient := d.Get("unsafe_ssl_client").(bool)
+	tokenLifetime, _ := time.ParseDuration(d.Get("token_lifetime").(string))
 
-	if (username == "") || (password == "") || (endpoint == "") {
+	if endpoint == "" {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Unable to create MKE client",
-			Detail:   "Unable to create anonymous MKE client",
+			Detail:   "No MKE endpoint was configured",
 		})
 
 		return nil, diags
 	}
 
-	var c client.Client
-	var clientErr error
+	tlsOptions, err := providerTLSOptions(d)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid MKE TLS configuration",
+			Detail:   err.Error(),
+		})
 
-	if unsafeClient {
-		c, clientErr = client.NewUnsafeSSLClient(endpoint, username, password)
-	} else {
-		c, clientErr = client.NewClientSimple(endpoint, username, password)
+		return nil, diags
 	}
 
-	if clientErr != nil {
+	auth, err := providerAuth(d, &tlsOptions)
+	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Unable to create MKE client",
-			Detail:   "Unable to authenticate user for authenticated MKE client",
+			Detail:   err.Error(),
 		})
 
 		return nil, diags
 	}
 
+	tlsOptions.InsecureSkipVerify = unsafeClient
+
+	tlsConfig, err := tlsOptions.TLSConfig()
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid MKE TLS configuration",
+			Detail:   err.Error(),
+		})
+
+		return nil, diags
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	httpClient := &http.Client{
+		Transport: transport,
+	}
+	// requests are logged when TF_LOG is DEBUG or TRACE, with their bodies at TRACE
+	if logging.IsDebugOrHigher() {
+		lrt := client.NewLoggingRoundTripper(transport)
+		lrt.LogBodies = logging.LogLevel() == "TRACE"
+		httpClient.Transport = lrt
+	}
+
+	apiURL, err := url.Parse(endpoint)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create MKE client",
+			Detail:   fmt.Sprintf("Invalid MKE endpoint: %s", err),
+		})
+
+		return nil, diags
+	}
+
+	c, err := client.NewClient(apiURL, &auth, httpClient)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create MKE client",
+			Detail:   err.Error(),
+		})
+
+		return nil, diags
+	}
+
+	c.TokenPolicy.Lifetime = tokenLifetime
+	if c.TokenPolicy.RenewBefore >= tokenLifetime {
+		c.TokenPolicy.RenewBefore = tokenLifetime / 10
+	}
+
+	c.RetryPolicy = providerRetryPolicy(d)
+
 	if err := c.ApiPing(ctx); err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
@@ -91,3 +310,141 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 
 	return c, diags
 }
+
+// providerAuth decide how the client authenticates from the provider attributes
+// A client bundle authenticates using its certificate, and a token or token file
+// is used as is. Otherwise a username and password are used to log in, and if
+// they are missing a client certificate alone can authenticate.
+// The client bundle certificates are added to the TLS options.
+func providerAuth(d *schema.ResourceData, tlsOptions *client.TLSOptions) (client.Auth, error) {
+	username := d.Get("username").(string)
+	password := d.Get("password").(string)
+
+	if token := d.Get("token").(string); token != "" {
+		if username == "" {
+			return client.Auth{}, ErrNoProviderTokenUsername
+		}
+		auth := client.NewAuthToken(token)
+		auth.Username = username
+		return auth, nil
+	}
+	if tokenFile := d.Get("token_file").(string); tokenFile != "" {
+		if _, err := os.Stat(tokenFile); err != nil {
+			return client.Auth{}, fmt.Errorf("%w; %s", client.ErrTokenFile, err)
+		}
+		if username == "" {
+			return client.Auth{}, ErrNoProviderTokenUsername
+		}
+		auth := client.NewAuthTokenFile(tokenFile)
+		auth.Username = username
+		return auth, nil
+	}
+
+	if bundlePath := d.Get("client_bundle").(string); bundlePath != "" {
+		cb, err := client.NewClientBundleFromPath(bundlePath)
+		if err != nil {
+			return client.Auth{}, err
+		}
+
+		if tlsOptions.CACertPEM == "" {
+			tlsOptions.CACertPEM = cb.CACert
+		}
+		tlsOptions.ClientCertPEM = cb.Cert
+		tlsOptions.ClientKeyPEM = cb.PrivateKey
+	} else if username != "" && password != "" {
+		auth := client.NewAuthUP(username, password)
+		auth.Code = d.Get("mfa_code").(string)
+		auth.TOTPSecret = d.Get("totp_secret").(string)
+
+		if auth.TOTPSecret != "" {
+			if _, err := client.TOTPCode(auth.TOTPSecret, time.Now()); err != nil {
+				return client.Auth{}, err
+			}
+		}
+
+		return auth, nil
+	}
+
+	if tlsOptions.ClientCertPEM == "" || tlsOptions.ClientKeyPEM == "" {
+		return client.Auth{}, ErrNoProviderAuth
+	}
+
+	if username == "" {
+		// MKE bundle certificates are issued to the account name
+		cb := client.ClientBundle{Cert: tlsOptions.ClientCertPEM}
+		username, _ = cb.CertCommonName()
+	}
+
+	return client.NewAuthClientCert(username), nil
+}
+
+// providerTLSOptions build the client TLSOptions from the provider TLS attributes
+func providerTLSOptions(d *schema.ResourceData) (client.TLSOptions, error) {
+	var opts client.TLSOptions
+	var err error
+
+	if opts.CACertPEM, err = providerPEMAttribute(d, "ca_cert", "ca_cert_file"); err != nil {
+		return opts, err
+	}
+	if opts.ClientCertPEM, err = providerPEMAttribute(d, "client_cert", "client_cert_file"); err != nil {
+		return opts, err
+	}
+	if opts.ClientKeyPEM, err = providerPEMAttribute(d, "client_key", "client_key_file"); err != nil {
+		return opts, err
+	}
+	if opts.MinVersion, err = client.TLSVersionFromString(d.Get("tls_min_version").(string)); err != nil {
+		return opts, err
+	}
+	opts.ServerName = d.Get("tls_server_name").(string)
+
+	return opts, nil
+}
+
+// providerPEMAttribute get a PEM value which can be set either inline or as a file path
+func providerPEMAttribute(d *schema.ResourceData, inlineKey, fileKey string) (string, error) {
+	if val := d.Get(inlineKey).(string); val != "" {
+		return val, nil
+	}
+	if path := d.Get(fileKey).(string); path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("could not read %s: %w", fileKey, err)
+		}
+		return string(b), nil
+	}
+	return "", nil
+}
+
+// providerRetryPolicy build the client RetryPolicy from the provider retry attributes
+func providerRetryPolicy(d *schema.ResourceData) client.RetryPolicy {
+	rp := client.NewRetryPolicyDefault()
+
+	rp.MaxAttempts = d.Get("retry_max_attempts").(int)
+	rp.MinBackoff, _ = time.ParseDuration(d.Get("retry_min_backoff").(string))
+	rp.MaxBackoff, _ = time.ParseDuration(d.Get("retry_max_backoff").(string))
+	rp.RetryNonIdempotent = d.Get("retry_non_idempotent").(bool)
+
+	if codes := d.Get("retry_status_codes").(*schema.Set).List(); len(codes) > 0 {
+		rp.RetryableStatusCodes = []int{}
+		for _, code := range codes {
+			rp.RetryableStatusCodes = append(rp.RetryableStatusCodes, code.(int))
+		}
+	}
+
+	return rp
+}
+
+// validateDuration schema validator for string attributes that hold a time.Duration
+func validateDuration(v interface{}, p cty.Path) diag.Diagnostics {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		return diag.Diagnostics{
+			{
+				Severity:      diag.Error,
+				Summary:       "Invalid duration",
+				Detail:        fmt.Sprintf("%q is not a valid duration: %s", v, err),
+				AttributePath: p,
+			},
+		}
+	}
+	return nil
+}
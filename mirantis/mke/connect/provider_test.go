@@ -1,8 +1,15 @@
 package connect_test
 
 import (
+	"crypto/tls"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
 	connect "github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/connect"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -16,3 +23,274 @@ func TestProvider(t *testing.T) {
 func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = connect.Provider()
 }
+
+// providerResourceData provider configuration data, ignoring any MKE_
+// environment variables of the test run
+func providerResourceData(t *testing.T, raw map[string]interface{}) *schema.ResourceData {
+	for _, env := range []string{
+		"MKE_ENDPOINT", "MKE_USER", "MKE_PASS", "MKE_MFA_CODE", "MKE_TOTP_SECRET",
+		"MKE_TOKEN", "MKE_TOKEN_FILE", "MKE_CLIENT_BUNDLE", "MKE_UNSAFE_CLIENT",
+		"MKE_CA_CERT", "MKE_CA_CERT_FILE", "MKE_TLS_SERVER_NAME", "MKE_TLS_MIN_VERSION",
+		"MKE_CLIENT_CERT", "MKE_CLIENT_CERT_FILE", "MKE_CLIENT_KEY", "MKE_CLIENT_KEY_FILE",
+		"MKE_TOKEN_LIFETIME", "MKE_RETRY_MAX_ATTEMPTS", "MKE_RETRY_MIN_BACKOFF",
+		"MKE_RETRY_MAX_BACKOFF", "MKE_RETRY_NON_IDEMPOTENT",
+	} {
+		t.Setenv(env, "")
+	}
+	return schema.TestResourceDataRaw(t, connect.Provider().Schema, raw)
+}
+
+// providerAuth the auth and TLS options for a provider configuration
+func providerAuth(t *testing.T, raw map[string]interface{}) (client.Auth, client.TLSOptions, error) {
+	d := providerResourceData(t, raw)
+
+	opts, err := connect.ProviderTLSOptions(d)
+	if err != nil {
+		t.Fatalf("Could not build the TLS options: %s", err)
+	}
+	auth, err := connect.ProviderAuth(d, &opts)
+	return auth, opts, err
+}
+
+// mockClientBundleDir a client bundle directory for an account
+func mockClientBundleDir(t *testing.T, account string) (string, client.ClientBundle) {
+	cert := mockCertificate(t, account)
+	cb := client.ClientBundle{
+		CACert:     mockCertificate(t, "myca"),
+		Cert:       cert,
+		PrivateKey: "mybundlekey",
+		PublicKey:  "mybundlepublickey",
+	}
+
+	dir := filepath.Join(t.TempDir(), "bundle")
+	if err := cb.WriteDir(dir); err != nil {
+		t.Fatalf("Could not write a client bundle: %s", err)
+	}
+	return dir, cb
+}
+
+func TestProviderAuthToken(t *testing.T) {
+	bundleDir, _ := mockClientBundleDir(t, "bundleuser")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	ioutil.WriteFile(tokenFile, []byte("filetoken"), 0600)
+
+	// a token is used before any other way of authenticating
+	auth, _, err := providerAuth(t, map[string]interface{}{
+		"token":         "mytoken",
+		"token_file":    tokenFile,
+		"client_bundle": bundleDir,
+		"username":      "myuser",
+		"password":      "mypassword",
+	})
+	if err != nil {
+		t.Fatalf("Token auth failed: %s", err)
+	}
+	if auth.Mode != client.AuthModeToken || auth.Token != "mytoken" || auth.Username != "myuser" {
+		t.Errorf("Token auth was not used: %+v", auth)
+	}
+
+	// then a token file
+	auth, _, err = providerAuth(t, map[string]interface{}{
+		"token_file":    tokenFile,
+		"client_bundle": bundleDir,
+		"username":      "myuser",
+		"password":      "mypassword",
+	})
+	if err != nil {
+		t.Fatalf("Token file auth failed: %s", err)
+	}
+	if auth.Mode != client.AuthModeToken || auth.TokenFile != tokenFile || auth.Token != "" {
+		t.Errorf("Token file auth was not used: %+v", auth)
+	}
+
+	_, _, err = providerAuth(t, map[string]interface{}{
+		"token_file": filepath.Join(t.TempDir(), "missing"),
+	})
+	if !errors.Is(err, client.ErrTokenFile) {
+		t.Errorf("Wrong error for a missing token file: %s", err)
+	}
+
+	// a token doesn't say which account it belongs to
+	for _, raw := range []map[string]interface{}{
+		{"token": "mytoken"},
+		{"token_file": tokenFile},
+	} {
+		if _, _, err := providerAuth(t, raw); !errors.Is(err, connect.ErrNoProviderTokenUsername) {
+			t.Errorf("Wrong error for a token without a username %+v: %s", raw, err)
+		}
+	}
+}
+
+func TestProviderAuthClientBundle(t *testing.T) {
+	bundleDir, cb := mockClientBundleDir(t, "bundleuser")
+
+	// a client bundle is used before a username and password
+	auth, opts, err := providerAuth(t, map[string]interface{}{
+		"client_bundle": bundleDir,
+		"password":      "mypassword",
+	})
+	if err != nil {
+		t.Fatalf("Client bundle auth failed: %s", err)
+	}
+	if auth.Mode != client.AuthModeClientCert || auth.Username != "bundleuser" {
+		t.Errorf("Client bundle auth was not used, with the certificate account: %+v", auth)
+	}
+	if opts.CACertPEM != cb.CACert || opts.ClientCertPEM != cb.Cert || opts.ClientKeyPEM != cb.PrivateKey {
+		t.Errorf("Client bundle certificates were not used for TLS: %+v", opts)
+	}
+
+	// a configured CA and username are kept
+	caCert := mockCertificate(t, "myotherca")
+	auth, opts, err = providerAuth(t, map[string]interface{}{
+		"client_bundle": bundleDir,
+		"ca_cert":       caCert,
+		"username":      "myuser",
+	})
+	if err != nil {
+		t.Fatalf("Client bundle auth failed: %s", err)
+	}
+	if auth.Username != "myuser" {
+		t.Errorf("Configured username was replaced by the certificate account: %+v", auth)
+	}
+	if opts.CACertPEM != caCert || opts.ClientCertPEM != cb.Cert {
+		t.Errorf("Configured CA was replaced by the client bundle CA: %+v", opts)
+	}
+
+	_, _, err = providerAuth(t, map[string]interface{}{
+		"client_bundle": filepath.Join(t.TempDir(), "missing"),
+	})
+	if !errors.Is(err, client.ErrFailedToReadClientBundle) {
+		t.Errorf("Wrong error for a missing client bundle: %s", err)
+	}
+}
+
+func TestProviderAuthPassword(t *testing.T) {
+	auth, _, err := providerAuth(t, map[string]interface{}{
+		"username":    "myuser",
+		"password":    "mypassword",
+		"mfa_code":    "123456",
+		"totp_secret": "JBSWY3DPEHPK3PXP",
+		// a client certificate is only presented alongside the login
+		"client_cert": mockCertificate(t, "certuser"),
+		"client_key":  "mykey",
+	})
+	if err != nil {
+		t.Fatalf("Password auth failed: %s", err)
+	}
+	if auth.Mode != client.AuthModePassword || auth.Username != "myuser" || auth.Password != "mypassword" {
+		t.Errorf("Password auth was not used: %+v", auth)
+	}
+	if auth.Code != "123456" || auth.TOTPSecret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Two factor options were not used: %+v", auth)
+	}
+
+	_, _, err = providerAuth(t, map[string]interface{}{
+		"username":    "myuser",
+		"password":    "mypassword",
+		"totp_secret": "not base32!",
+	})
+	if err == nil {
+		t.Error("An invalid TOTP secret was accepted")
+	}
+}
+
+func TestProviderAuthClientCert(t *testing.T) {
+	cert := mockCertificate(t, "certuser")
+
+	// a client certificate alone authenticates as its account
+	auth, opts, err := providerAuth(t, map[string]interface{}{
+		"client_cert": cert,
+		"client_key":  "mykey",
+	})
+	if err != nil {
+		t.Fatalf("Client certificate auth failed: %s", err)
+	}
+	if auth.Mode != client.AuthModeClientCert || auth.Username != "certuser" {
+		t.Errorf("Client certificate auth was not used, with the certificate account: %+v", auth)
+	}
+	if opts.ClientCertPEM != cert || opts.ClientKeyPEM != "mykey" {
+		t.Errorf("Client certificate was not used for TLS: %+v", opts)
+	}
+
+	// a username without a password falls back to the certificate too
+	auth, _, err = providerAuth(t, map[string]interface{}{
+		"username":    "myuser",
+		"client_cert": cert,
+		"client_key":  "mykey",
+	})
+	if err != nil || auth.Mode != client.AuthModeClientCert || auth.Username != "myuser" {
+		t.Errorf("Client certificate auth did not keep the username: %+v, %s", auth, err)
+	}
+}
+
+func TestProviderAuthNone(t *testing.T) {
+	for _, raw := range []map[string]interface{}{
+		{},
+		{"username": "myuser"},
+		{"password": "mypassword"},
+		{"client_cert": mockCertificate(t, "certuser")},
+	} {
+		if _, _, err := providerAuth(t, raw); !errors.Is(err, connect.ErrNoProviderAuth) {
+			t.Errorf("Wrong error for %+v: %s", raw, err)
+		}
+	}
+}
+
+func TestProviderTLSOptions(t *testing.T) {
+	caCert := mockCertificate(t, "myca")
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ioutil.WriteFile(caFile, []byte(caCert), 0600)
+
+	d := providerResourceData(t, map[string]interface{}{
+		"ca_cert_file":    caFile,
+		"tls_server_name": "mke.internal",
+		"tls_min_version": "1.3",
+	})
+	opts, err := connect.ProviderTLSOptions(d)
+	if err != nil {
+		t.Fatalf("Could not build the TLS options: %s", err)
+	}
+	if opts.CACertPEM != caCert || opts.ServerName != "mke.internal" || opts.MinVersion != tls.VersionTLS13 {
+		t.Errorf("TLS options were not used: %+v", opts)
+	}
+
+	// inline values are used before files
+	d = providerResourceData(t, map[string]interface{}{
+		"ca_cert":      "inlineca",
+		"ca_cert_file": caFile,
+	})
+	if opts, err := connect.ProviderTLSOptions(d); err != nil || opts.CACertPEM != "inlineca" || opts.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Inline CA or the default TLS version was not used: %+v, %s", opts, err)
+	}
+
+	d = providerResourceData(t, map[string]interface{}{
+		"client_key_file": filepath.Join(t.TempDir(), "missing"),
+	})
+	if _, err := connect.ProviderTLSOptions(d); err == nil {
+		t.Error("A missing client key file was accepted")
+	}
+}
+
+func TestProviderRetryPolicy(t *testing.T) {
+	rp := connect.ProviderRetryPolicy(providerResourceData(t, map[string]interface{}{}))
+	if rp.MaxAttempts != client.DefaultRetryMaxAttempts || rp.MinBackoff != client.DefaultRetryMinBackoff || rp.MaxBackoff != client.DefaultRetryMaxBackoff || rp.RetryNonIdempotent {
+		t.Errorf("Default retry policy was not used: %+v", rp)
+	}
+	if len(rp.RetryableStatusCodes) != len(client.DefaultRetryableStatusCodes) {
+		t.Errorf("Default retryable status codes were not used: %+v", rp.RetryableStatusCodes)
+	}
+
+	rp = connect.ProviderRetryPolicy(providerResourceData(t, map[string]interface{}{
+		"retry_max_attempts":   6,
+		"retry_min_backoff":    "2s",
+		"retry_max_backoff":    "1m",
+		"retry_status_codes":   []interface{}{http.StatusBadGateway},
+		"retry_non_idempotent": true,
+	}))
+	if rp.MaxAttempts != 6 || rp.MinBackoff != 2*time.Second || rp.MaxBackoff != time.Minute || !rp.RetryNonIdempotent {
+		t.Errorf("Configured retry policy was not used: %+v", rp)
+	}
+	if len(rp.RetryableStatusCodes) != 1 || rp.RetryableStatusCodes[0] != http.StatusBadGateway {
+		t.Errorf("Configured retryable status codes were not used: %+v", rp.RetryableStatusCodes)
+	}
+}
@@ -0,0 +1,231 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ResourceAccountPublicKey for registering externally generated public keys with MKE
+// This is a client bundle without the private key, which never leaves the
+// system that generated it.
+func ResourceAccountPublicKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAccountPublicKeyCreate,
+		ReadContext:   resourceAccountPublicKeyRead,
+		UpdateContext: resourceAccountPublicKeyUpdate,
+		DeleteContext: resourceAccountPublicKeyDelete,
+		Schema: map[string]*schema.Schema{
+			"account": {
+				Type:        schema.TypeString,
+				Description: "Account the public key is for, if not the account that the provider logs in as.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"public_key": {
+				Type:             schema.TypeString,
+				Description:      "PEM encoded public key.",
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressWhitespaceDiff,
+			},
+			"label": {
+				Type:        schema.TypeString,
+				Description: "Label of the public key in MKE.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"certificate": {
+				Type:        schema.TypeList,
+				Description: "Certificates for the public key to register with it.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"cert": {
+							Type:        schema.TypeString,
+							Description: "PEM encoded certificate.",
+							Required:    true,
+							ForceNew:    true,
+						},
+					},
+				},
+			},
+
+			"key_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the public key in MKE.",
+				Computed:    true,
+			},
+			"certificate_chain": {
+				Type:        schema.TypeList,
+				Description: "PEM encoded certificates that MKE holds for the public key.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceAccountPublicKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	account := d.Get("account").(string)
+	if account == "" {
+		account = c.Username()
+	}
+
+	certs := []client.Certificate{}
+	for _, item := range d.Get("certificate").([]interface{}) {
+		cert := item.(map[string]interface{})
+		certs = append(certs, client.Certificate{
+			Label: cert["label"].(string),
+			Cert:  cert["cert"].(string),
+		})
+	}
+
+	key, err := c.ApiPublicKeyCreate(ctx, account, d.Get("public_key").(string), d.Get("label").(string), certs)
+	if err != nil {
+		diags = append(diags, diag.Errorf("MKE Client could not register the public key: %s", err)...)
+		return diags
+	}
+
+	if key.ID == "" {
+		diags = append(diags, diag.Errorf("MKE registered the public key, but did not return its ID")...)
+		return diags
+	}
+
+	d.SetId(joinID(account, key.ID))
+
+	diags = append(diags, resourceAccountPublicKeySet(d, account, key)...)
+
+	return diags
+}
+
+func resourceAccountPublicKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	parts, ok := splitID(d.Id(), 2)
+	if !ok {
+		diags = append(diags, diag.FromErr(fmt.Errorf("%w; public key IDs are account/keyID, got %q", ErrInvalidID, d.Id()))...)
+		return diags
+	}
+	account, keyID := parts[0], parts[1]
+
+	key, err := c.ApiPublicKeyRetrieve(ctx, account, keyID)
+	if errors.Is(err, client.ErrUnknownTarget) {
+		// the key was removed from MKE, so it should be removed from state
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+		return diags
+	}
+	if key.ID == "" {
+		key.ID = keyID
+	}
+
+	diags = append(diags, resourceAccountPublicKeySet(d, account, key)...)
+
+	return diags
+}
+
+// Only the label of a public key can be changed, anything else replaces the key
+func resourceAccountPublicKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if d.HasChange("label") {
+		if _, err := c.ApiPublicKeyUpdateLabel(ctx, d.Get("account").(string), d.Get("key_id").(string), d.Get("label").(string)); err != nil {
+			diags = append(diags, diag.Errorf("MKE Client could not update the public key label: %s", err)...)
+		}
+	}
+
+	return diags
+}
+
+func resourceAccountPublicKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if err := c.ApiPublicKeyDelete(ctx, d.Get("account").(string), d.Get("key_id").(string)); err != nil && !errors.Is(err, client.ErrUnknownTarget) {
+		diags = append(diags, diag.Errorf("MKE Client could not delete the public key: %s", err)...)
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourceAccountPublicKeySet set the attributes from an MKE public key
+func resourceAccountPublicKeySet(d *schema.ResourceData, account string, key client.AccountPublicKey) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	chain := []string{}
+	for _, cert := range key.Certificates {
+		chain = append(chain, cert.Cert)
+	}
+
+	attrs := map[string]interface{}{
+		"account":           account,
+		"key_id":            key.ID,
+		"label":             key.Label,
+		"certificate_chain": chain,
+	}
+	if key.PublicKey != "" {
+		attrs["public_key"] = key.PublicKey
+	}
+
+	for attr, val := range attrs {
+		if err := d.Set(attr, val); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+// suppressWhitespaceDiff ignore leading and trailing whitespace changes, which
+// MKE makes to PEM values
+func suppressWhitespaceDiff(k, old, new string, d *schema.ResourceData) bool {
+	return strings.TrimSpace(old) == strings.TrimSpace(new)
+}
@@ -0,0 +1,128 @@
+package connect_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	connect "github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/connect"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccountPublicKeyCreate(t *testing.T) {
+	cert := mockCertificate(t, "myservice")
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodPost + " " + fmt.Sprintf(client.URLTargetPatternForPublicKeys, "myservice"): func(w http.ResponseWriter, r *http.Request) {
+			var create client.AccountPublicKey
+			if err := json.NewDecoder(r.Body).Decode(&create); err != nil || len(create.Certificates) != 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			create.ID = "NEWKEY"
+			create.PublicKey += "\n"
+			mockReturnJSON(create)(w, r)
+		},
+	})
+
+	r := connect.ResourceAccountPublicKey()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"account":    "myservice",
+		"public_key": "mypublickey",
+		"label":      "hsm",
+		"certificate": []interface{}{
+			map[string]interface{}{"label": "csr", "cert": cert},
+		},
+	})
+
+	if diags := r.CreateContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Create failed: %+v", diags)
+	}
+	if d.Id() != "myservice/NEWKEY" || d.Get("key_id").(string) != "NEWKEY" {
+		t.Errorf("Create set the wrong ID: %s", d.Id())
+	}
+	if chain := d.Get("certificate_chain").([]interface{}); len(chain) != 1 || chain[0].(string) != cert {
+		t.Errorf("Create set the wrong certificate chain: %+v", chain)
+	}
+}
+
+func TestAccountPublicKeyImport(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, "myservice", "MYKEY"): mockReturnJSON(client.AccountPublicKey{
+			ID:        "MYKEY",
+			Label:     "hsm",
+			PublicKey: "mypublickey",
+		}),
+	})
+
+	r := connect.ResourceAccountPublicKey()
+	d := r.TestResourceData()
+	d.SetId("myservice/MYKEY")
+
+	ds, err := r.Importer.StateContext(context.Background(), d, c)
+	if err != nil || len(ds) != 1 {
+		t.Fatalf("Import failed: %s", err)
+	}
+	d = ds[0]
+
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read failed: %+v", diags)
+	}
+	if d.Get("account").(string) != "myservice" || d.Get("key_id").(string) != "MYKEY" || d.Get("label").(string) != "hsm" || d.Get("public_key").(string) != "mypublickey" {
+		t.Errorf("Read set the wrong attributes: %+v", d.State())
+	}
+}
+
+func TestAccountPublicKeyReadDeleted(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{})
+
+	r := connect.ResourceAccountPublicKey()
+	d := r.TestResourceData()
+	d.SetId("myservice/MYKEY")
+
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read of a deleted key failed: %+v", diags)
+	}
+	if d.Id() != "" {
+		t.Error("Read did not remove a deleted key from state")
+	}
+}
+
+func TestAccountPublicKeyReadInvalidID(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{})
+
+	r := connect.ResourceAccountPublicKey()
+	d := r.TestResourceData()
+	d.SetId("MYKEY")
+
+	if diags := r.ReadContext(context.Background(), d, c); !diags.HasError() {
+		t.Error("Read of an invalid ID did not fail")
+	}
+}
+
+func TestAccountPublicKeyDelete(t *testing.T) {
+	deleted := false
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodDelete + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, "myservice", "MYKEY"): func(w http.ResponseWriter, r *http.Request) {
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+
+	r := connect.ResourceAccountPublicKey()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"account":    "myservice",
+		"public_key": "mypublickey",
+	})
+	d.SetId("myservice/MYKEY")
+	d.Set("key_id", "MYKEY")
+
+	if diags := r.DeleteContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Delete failed: %+v", diags)
+	}
+	if !deleted || d.Id() != "" {
+		t.Error("Delete did not delete the key")
+	}
+}
@@ -4,27 +4,85 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 var (
-	ErrCBNotFound = errors.New("Client bundle was not found on the MKE host")
+	ErrCBNotFound        = errors.New("Client bundle was not found on the MKE host")
+	ErrCBInvalidImport   = errors.New("Client bundle import ID must be in the format account/keyID")
+	ErrCBInvalidID       = errors.New("Client bundle ID must be in the format account/keyID")
+	ErrCBAccountPassword = errors.New("Client bundle for another account needs the account password")
+	ErrCBRotateBefore    = errors.New("Client bundle rotate_before must be shorter than the certificate lifetime")
 )
 
 // ResourceClientBundle for managing MKE Client Bundles
+// Bundles are identified by account/keyID, the MKE public key of the bundle.
 func ResourceClientBundle() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
+		SchemaVersion: 1,
 		CreateContext: resourceClientBundleCreate,
 		ReadContext:   resourceClientBundleRead,
 		UpdateContext: resourceClientBundleUpdate,
 		DeleteContext: resourceClientBundleDelete,
+		CustomizeDiff: resourceClientBundleCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Description: "Label of the client bundle public key in MKE.",
+				Required:    true,
+			},
+			"account": {
+				Type:        schema.TypeString,
+				Description: "Account the client bundle is for, if not the account that the provider logs in as.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Description: "Username to log in as the account to generate the client bundle, defaults to the account.",
+				Optional:    true,
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Description: "Password to log in as the account to generate the client bundle.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"rotate_before": {
+				Type:             schema.TypeString,
+				Description:      "Replace the client bundle when its certificate expires within this duration, such as 720h.",
+				Optional:         true,
+				ValidateDiagFunc: validateDuration,
+			},
+			"keepers": {
+				Type:        schema.TypeMap,
+				Description: "Arbitrary values which replace the client bundle when they change.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ready_for_renewal": {
+				Type:        schema.TypeBool,
+				Description: "The certificate has expired, or expires within rotate_before, so the client bundle will be replaced.",
+				Computed:    true,
+			},
+			"output_path": {
+				Type:        schema.TypeString,
+				Description: "Directory to write the client bundle files into, which are re-written if they change or are removed.",
+				Optional:    true,
+			},
+			"output_path_drift": {
+				Type:        schema.TypeBool,
+				Description: "The files in output_path were changed or removed, so they will be written again.",
+				Computed:    true,
 			},
 
 			"private_key": {
@@ -36,6 +94,11 @@ func ResourceClientBundle() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"public_key_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the client bundle public key in MKE.",
+				Computed:    true,
+			},
 			"ca_cert": {
 				Type:      schema.TypeString,
 				Computed:  true,
@@ -45,6 +108,88 @@ func ResourceClientBundle() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"docker_host": {
+				Type:        schema.TypeString,
+				Description: "Docker endpoint from the client bundle docker context.",
+				Computed:    true,
+			},
+			"env": {
+				Type:        schema.TypeMap,
+				Description: "Environment variables exported by the client bundle env.sh.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"files": {
+				Type:        schema.TypeMap,
+				Description: "Contents of every file in the client bundle, by path.",
+				Computed:    true,
+				Sensitive:   true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"certificate": {
+				Type:        schema.TypeList,
+				Description: "Metadata of the client bundle certificate, such as its expiry.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"subject": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"common_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"issuer": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"serial_number": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"dns_names": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"ip_addresses": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"email_addresses": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"uris": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"not_before": {
+							Type:        schema.TypeString,
+							Description: "Start of the certificate validity, in RFC3339 format.",
+							Computed:    true,
+						},
+						"not_after": {
+							Type:        schema.TypeString,
+							Description: "End of the certificate validity, in RFC3339 format.",
+							Computed:    true,
+						},
+						"fingerprint_sha256": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 
 			"kube": {
 				Type:        schema.TypeList,
@@ -81,26 +226,78 @@ func ResourceClientBundle() *schema.Resource {
 			},
 		},
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceClientBundleImport,
 		},
 	}
+
+	// version 0 bundles were identified by name, but had the same attributes
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    r.CoreConfigSchema().ImpliedType(),
+			Upgrade: resourceClientBundleStateUpgradeV0,
+		},
+	}
+
+	return r
 }
 
 func resourceClientBundleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c, ok := m.(client.Client)
+	c, ok := m.(*client.Client)
 	if !ok {
 		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
 		return diags
 	}
 
-	cb, err := c.ApiClientBundleCreate(ctx)
+	label := d.Get("name").(string)
+	account := resourceClientBundleAccount(d, c)
+
+	var cb client.ClientBundle
+	var err error
+	if account != c.Username() {
+		// MKE only generates bundles for the logged in account
+		username := d.Get("username").(string)
+		if username == "" {
+			username = account
+		}
+		password := d.Get("password").(string)
+		if password == "" {
+			diags = append(diags, diag.FromErr(fmt.Errorf("%w; %s", ErrCBAccountPassword, account))...)
+			return diags
+		}
+		cb, err = c.ApiClientBundleCreateForAccount(ctx, client.NewAuthUP(username, password), label)
+		cb.Account = account
+	} else {
+		cb, err = c.ApiClientBundleCreate(ctx, label)
+	}
 	if err != nil {
 		diags = append(diags, diag.FromErr(err)...)
 		return diags
 	}
 
+	key, err := c.ApiClientBundleGetPublicKey(ctx, cb)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not find the client bundle public key in MKE",
+			Detail:   fmt.Sprintf("The client bundle %q was created, but can't be managed without its public key, so it has to be deleted in MKE: %s", label, err),
+		})
+		return diags
+	}
+
+	// the bundle exists in MKE now, so any later failure has to taint it rather
+	// than leave its public key behind
+	d.SetId(joinID(account, key.ID))
+
+	if err := d.Set("account", account); err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+	}
+	if err := d.Set("public_key_id", key.ID); err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+	}
+
 	if err := d.Set("private_key", cb.PrivateKey); err != nil {
 		diags = append(diags, diag.FromErr(err)...)
 	}
@@ -113,6 +310,17 @@ func resourceClientBundleCreate(ctx context.Context, d *schema.ResourceData, m i
 	if err := d.Set("client_cert", cb.Cert); err != nil {
 		diags = append(diags, diag.FromErr(err)...)
 	}
+	if err := d.Set("docker_host", cb.DockerHost()); err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+	}
+	if err := d.Set("env", cb.Env); err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+	}
+	if err := d.Set("files", cb.Files); err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+	}
+
+	diags = append(diags, resourceClientBundleSetCertificate(d, cb)...)
 
 	kc := cb.Kube
 	if kc == nil {
@@ -133,8 +341,13 @@ func resourceClientBundleCreate(ctx context.Context, d *schema.ResourceData, m i
 
 	}
 
-	if !diags.HasError() {
-		d.SetId(d.Get("name").(string))
+	if outputPath := d.Get("output_path").(string); outputPath != "" {
+		if err := cb.WriteDir(outputPath); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+	if err := d.Set("output_path_drift", false); err != nil {
+		diags = append(diags, diag.FromErr(err)...)
 	}
 
 	return diags
@@ -143,55 +356,341 @@ func resourceClientBundleCreate(ctx context.Context, d *schema.ResourceData, m i
 func resourceClientBundleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c, ok := m.(client.Client)
+	c, ok := m.(*client.Client)
 	if !ok {
 		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
 		return diags
 	}
 
-	cb := client.ClientBundle{
-		PrivateKey: d.Get("public_key").(string),
-		PublicKey:  d.Get("private_key").(string),
-	}
-
-	if err := c.ApiPing(ctx); err != nil {
-		// state confirmation failed because we couldn't reach the client
+	found, err := resourceClientBundleReadPublicKey(ctx, c, d)
+	if err != nil {
+		// state confirmation failed, most likely because we couldn't reach MKE
 		diags = append(diags, diag.FromErr(err)...)
-	} else if _, err := c.ApiClientBundleGetPublicKey(ctx, cb); err == nil {
-		d.SetId(d.Get("name").(string))
-	} else if cb.PrivateKey == "" {
+		return diags
+	}
+	if !found {
 		// we have a bundle in state, but it doesn't exist in MKE so it should be removed
-		// @todo check that we haven't suffered from a connectivity failure
+		tflog.Warn(ctx, "removing client bundle from state",
+			"id", d.Id(),
+			"error", ErrCBNotFound.Error(),
+		)
 		d.SetId("")
-		diags = append(diags, diag.FromErr(fmt.Errorf("%w; %s", ErrCBNotFound, err))...)
+		return diags
+	}
+
+	// bundles created before the certificate metadata was kept get it from their cert
+	if cert := d.Get("client_cert").(string); cert != "" {
+		diags = append(diags, resourceClientBundleSetCertificate(d, client.ClientBundle{Cert: cert})...)
+	}
+
+	// files which drifted are flagged, so that the plan writes them again
+	drift := false
+	if outputPath := d.Get("output_path").(string); outputPath != "" {
+		drift = !clientBundleFromResourceData(d).MatchesDir(outputPath)
+	}
+	if err := d.Set("output_path_drift", drift); err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+	}
+
+	return diags
+}
+
+// resourceClientBundleReadPublicKey confirm that the bundle public key still
+// exists in MKE, refreshing the label and key ID.
+// A key which is missing is reported as not found, any other failure is an error.
+func resourceClientBundleReadPublicKey(ctx context.Context, c *client.Client, d *schema.ResourceData) (bool, error) {
+	account, keyID, ok := resourceClientBundleID(d)
+	if !ok {
+		// only a bundle which was already gone when its state was upgraded has no key ID
+		return false, nil
 	}
 
-	return diag.Diagnostics{}
+	key, err := c.ApiPublicKeyRetrieve(ctx, account, keyID)
+	if errors.Is(err, client.ErrUnknownTarget) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if err := d.Set("account", account); err != nil {
+		return true, err
+	}
+	if err := d.Set("public_key_id", key.ID); err != nil {
+		return true, err
+	}
+	if key.Label != "" {
+		if err := d.Set("name", key.Label); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
 }
 
-// Resources can't be done as it is imposible to update a public key, it can just be recreated
+// The bundle itself can't be updated as it is imposible to update a public key,
+// it can just be recreated. Only the public key label and the files written to
+// output_path are managed here.
 func resourceClientBundleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	return diag.Diagnostics{}
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if d.HasChange("name") {
+		account, keyID, ok := resourceClientBundleID(d)
+		if !ok {
+			diags = append(diags, diag.FromErr(fmt.Errorf("%w; got %q", ErrCBInvalidID, d.Id()))...)
+			return diags
+		}
+
+		if _, err := c.ApiPublicKeyUpdateLabel(ctx, account, keyID, d.Get("name").(string)); err != nil {
+			diags = append(diags, diag.Errorf("MKE Client could not update the client bundle label: %s", err)...)
+			return diags
+		}
+	}
+
+	if d.HasChanges("output_path", "output_path_drift") {
+		cb := clientBundleFromResourceData(d)
+		oldPath, newPath := d.GetChange("output_path")
+
+		if oldPath := oldPath.(string); oldPath != "" && oldPath != newPath.(string) {
+			if err := cb.RemoveDir(oldPath); err != nil {
+				diags = append(diags, diag.FromErr(err)...)
+			}
+		}
+		if newPath := newPath.(string); newPath != "" {
+			if err := cb.WriteDir(newPath); err != nil {
+				diags = append(diags, diag.FromErr(err)...)
+				return diags
+			}
+		}
+		if err := d.Set("output_path_drift", false); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
 }
 
 func resourceClientBundleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c, ok := m.(client.Client)
+	c, ok := m.(*client.Client)
 	if !ok {
 		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
 		return diags
 	}
 
+	// a bundle without a key ID was already gone from MKE
+	if account, keyID, ok := resourceClientBundleID(d); ok {
+		err := c.ApiPublicKeyDelete(ctx, account, keyID)
+		if err != nil && !errors.Is(err, client.ErrUnknownTarget) {
+			diags = append(diags, diag.Errorf("MKE Client could not delete the client bundle: %s", err)...)
+			return diags
+		}
+	}
+
+	if outputPath := d.Get("output_path").(string); outputPath != "" {
+		if err := clientBundleFromResourceData(d).RemoveDir(outputPath); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+			return diags
+		}
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourceClientBundleImport adopt an existing bundle by its public key, using
+// an ID of account/keyID.
+// The private key can't be recovered from MKE, so imported bundles have no
+// private key, kube config or files, but can be relabeled, rotated and deleted.
+func resourceClientBundleImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c, ok := m.(*client.Client)
+	if !ok {
+		return nil, errors.New("unable to cast meta interface to MKE Client")
+	}
+
+	parts, ok := splitID(d.Id(), 2)
+	if !ok {
+		return nil, fmt.Errorf("%w; got %q", ErrCBInvalidImport, d.Id())
+	}
+	account, keyID := parts[0], parts[1]
+
+	key, err := c.ApiPublicKeyRetrieve(ctx, account, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("account", account); err != nil {
+		return nil, err
+	}
+	if err := d.Set("name", key.Label); err != nil {
+		return nil, err
+	}
+	if err := d.Set("public_key", key.PublicKey); err != nil {
+		return nil, err
+	}
+	if err := d.Set("public_key_id", keyID); err != nil {
+		return nil, err
+	}
+
+	if len(key.Certificates) > 0 {
+		cb := client.ClientBundle{Cert: key.Certificates[0].Cert}
+		if err := d.Set("client_cert", cb.Cert); err != nil {
+			return nil, err
+		}
+		if diags := resourceClientBundleSetCertificate(d, cb); diags.HasError() {
+			return nil, errors.New(diags[0].Summary)
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceClientBundleStateUpgradeV0 identify a bundle by account/keyID rather
+// than by its name, finding the key ID of bundles which didn't keep it.
+// A bundle whose public key is already gone keeps an ID without a key ID, so
+// that the next refresh removes it from state.
+func resourceClientBundleStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, m interface{}) (map[string]interface{}, error) {
+	c, ok := m.(*client.Client)
+	if !ok {
+		return nil, errors.New("unable to cast meta interface to MKE Client")
+	}
+
+	account, _ := rawState["account"].(string)
+	if account == "" {
+		account = c.Username()
+	}
+
+	keyID, _ := rawState["public_key_id"].(string)
+	if keyID == "" {
+		publicKey, _ := rawState["public_key"].(string)
+		key, err := c.ApiClientBundleGetPublicKey(ctx, client.ClientBundle{Account: account, PublicKey: publicKey})
+		if err != nil && !errors.Is(err, client.ErrFailedToFindClientBundleMKEPublicKey) {
+			return nil, err
+		}
+		keyID = key.ID
+	}
+
+	rawState["id"] = joinID(account, keyID)
+	rawState["account"] = account
+	rawState["public_key_id"] = keyID
+
+	return rawState, nil
+}
+
+// resourceClientBundleID the account and public key ID from the bundle ID
+func resourceClientBundleID(d *schema.ResourceData) (string, string, bool) {
+	parts, ok := splitID(d.Id(), 2)
+	if !ok {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// resourceClientBundleAccount the account the bundle belongs to, which is the
+// provider account unless another was given
+func resourceClientBundleAccount(d *schema.ResourceData, c *client.Client) string {
+	if account := d.Get("account").(string); account != "" {
+		return account
+	}
+	return c.Username()
+}
+
+// resourceClientBundleSetCertificate set the certificate metadata attributes
+func resourceClientBundleSetCertificate(d *schema.ResourceData, cb client.ClientBundle) diag.Diagnostics {
+	ci, err := cb.CertificateInfo()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	m := map[string]interface{}{
+		"subject":            ci.Subject,
+		"common_name":        ci.CommonName,
+		"issuer":             ci.Issuer,
+		"serial_number":      ci.SerialNumber,
+		"dns_names":          ci.DNSNames,
+		"ip_addresses":       ci.IPAddresses,
+		"email_addresses":    ci.EmailAddresses,
+		"uris":               ci.URIs,
+		"not_before":         ci.NotBefore.UTC().Format(time.RFC3339),
+		"not_after":          ci.NotAfter.UTC().Format(time.RFC3339),
+		"fingerprint_sha256": ci.FingerprintSHA256,
+	}
+
+	if err := d.Set("certificate", []interface{}{m}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	rotateBefore, _ := time.ParseDuration(d.Get("rotate_before").(string))
+	if err := d.Set("ready_for_renewal", ci.ExpiresIn(time.Now()) <= rotateBefore); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// resourceClientBundleCustomizeDiff replace bundles whose certificate expires
+// within rotate_before, and write files which drifted again
+// The renewal window is worked out from the planned rotate_before, so that a
+// new or wider window takes effect in the same plan.
+func resourceClientBundleCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.Get("output_path_drift").(bool) && d.Get("output_path").(string) != "" {
+		if err := d.SetNew("output_path_drift", false); err != nil {
+			return err
+		}
+	}
+
+	notAfter, err := time.Parse(time.RFC3339, d.Get("certificate.0.not_after").(string))
+	if err != nil {
+		// without certificate metadata there is nothing to renew against
+		return nil
+	}
+	rotateBefore, _ := time.ParseDuration(d.Get("rotate_before").(string))
+
+	if notBefore, err := time.Parse(time.RFC3339, d.Get("certificate.0.not_before").(string)); err == nil {
+		if lifetime := notAfter.Sub(notBefore); rotateBefore >= lifetime {
+			return fmt.Errorf("%w; rotate_before %s, certificate lifetime %s", ErrCBRotateBefore, rotateBefore, lifetime)
+		}
+	}
+
+	if time.Until(notAfter) > rotateBefore {
+		return nil
+	}
+
+	// the replacement bundle has a new certificate
+	if err := d.SetNewComputed("certificate"); err != nil {
+		return err
+	}
+	return d.ForceNew("certificate")
+}
+
+// clientBundleFromResourceData rebuild the client bundle files from resource state
+func clientBundleFromResourceData(d *schema.ResourceData) client.ClientBundle {
 	cb := client.ClientBundle{
-		PublicKey: d.Get("public_key").(string),
+		ID:         d.Id(),
+		PrivateKey: d.Get("private_key").(string),
+		PublicKey:  d.Get("public_key").(string),
+		CACert:     d.Get("ca_cert").(string),
+		Cert:       d.Get("client_cert").(string),
+		Files:      map[string]string{},
 	}
 
-	if err := c.ApiClientBundleDelete(ctx, cb); err != nil {
-		diags = append(diags, diag.Errorf("MKE Client could not delete the client bundle: %s", err)...)
-	} else {
-		d.SetId("")
+	for name, contents := range d.Get("files").(map[string]interface{}) {
+		cb.Files[name] = contents.(string)
+	}
+	if config, ok := d.GetOk("kube.0.config_yml"); ok {
+		cb.Kube = &client.ClientBundleKube{
+			Config: config.(string),
+		}
 	}
 
-	return diag.Diagnostics{}
+	return cb
 }
@@ -0,0 +1,618 @@
+package connect_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	connect "github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/connect"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// clientBundleResourceData resource data for a bundle which is already in state,
+// identified by its account and public_key_id
+func clientBundleResourceData(t *testing.T, attrs map[string]interface{}) *schema.ResourceData {
+	r := connect.ResourceClientBundle()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"name": "mybundle"})
+
+	account, ok := attrs["account"].(string)
+	if !ok {
+		account = mockUsername
+	}
+	keyID, _ := attrs["public_key_id"].(string)
+	d.SetId(account + "/" + keyID)
+
+	for key, val := range attrs {
+		if err := d.Set(key, val); err != nil {
+			t.Fatalf("Could not set %s: %s", key, err)
+		}
+	}
+	return d
+}
+
+func TestClientBundleReadByKeyID(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, mockUsername, "MINE"): mockReturnJSON(client.AccountPublicKey{
+			ID:        "MINE",
+			Label:     "renamed",
+			PublicKey: "mypublickey",
+		}),
+	})
+
+	d := clientBundleResourceData(t, map[string]interface{}{
+		"public_key":    "mypublickey",
+		"public_key_id": "MINE",
+	})
+
+	if diags := connect.ResourceClientBundle().ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read failed: %+v", diags)
+	}
+	if d.Id() == "" {
+		t.Fatal("Read removed a bundle that exists")
+	}
+	if d.Get("name").(string) != "renamed" {
+		t.Errorf("Read did not pick up the label change: %s", d.Get("name"))
+	}
+}
+
+func TestClientBundleStateUpgradeV0(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKeys, mockUsername): mockReturnJSON(client.GetKeysResponse{
+			AccountPubKeys: []client.AccountPublicKey{
+				{ID: "OTHER", PublicKey: "otherpublickey"},
+				{ID: "MINE", PublicKey: "mypublickey"},
+			},
+		}),
+	})
+	upgrader := connect.ResourceClientBundle().StateUpgraders[0]
+
+	// bundles which didn't keep their key ID find it by public key
+	state, err := upgrader.Upgrade(context.Background(), map[string]interface{}{
+		"id":         "mybundle",
+		"name":       "mybundle",
+		"public_key": "mypublickey",
+	}, c)
+	if err != nil {
+		t.Fatalf("State upgrade failed: %s", err)
+	}
+	if state["id"] != mockUsername+"/MINE" || state["public_key_id"] != "MINE" || state["account"] != mockUsername {
+		t.Errorf("State upgrade set the wrong ID: %+v", state)
+	}
+
+	// bundles with a key ID don't need MKE
+	state, err = upgrader.Upgrade(context.Background(), map[string]interface{}{
+		"id":            "mybundle",
+		"name":          "mybundle",
+		"account":       "myservice",
+		"public_key_id": "SERVICEKEY",
+	}, c)
+	if err != nil {
+		t.Fatalf("State upgrade failed: %s", err)
+	}
+	if state["id"] != "myservice/SERVICEKEY" {
+		t.Errorf("State upgrade set the wrong ID: %+v", state)
+	}
+}
+
+func TestClientBundleStateUpgradeV0Deleted(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKeys, mockUsername): mockReturnJSON(client.GetKeysResponse{
+			AccountPubKeys: []client.AccountPublicKey{
+				{ID: "OTHER", PublicKey: "otherpublickey"},
+			},
+		}),
+	})
+	r := connect.ResourceClientBundle()
+
+	state, err := r.StateUpgraders[0].Upgrade(context.Background(), map[string]interface{}{
+		"id":         "mybundle",
+		"name":       "mybundle",
+		"public_key": "mypublickey",
+	}, c)
+	if err != nil {
+		t.Fatalf("State upgrade of a deleted bundle failed: %s", err)
+	}
+
+	// the next refresh removes the bundle which is already gone
+	d := r.TestResourceData()
+	d.SetId(state["id"].(string))
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read of a deleted bundle failed: %+v", diags)
+	}
+	if d.Id() != "" {
+		t.Error("Read did not remove a bundle whose public key was already gone from state")
+	}
+}
+
+func TestClientBundleReadDeletedKeyID(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, mockUsername, "MINE"): mockReturnStatus(http.StatusNotFound),
+	})
+
+	d := clientBundleResourceData(t, map[string]interface{}{
+		"public_key":    "mypublickey",
+		"public_key_id": "MINE",
+	})
+
+	if diags := connect.ResourceClientBundle().ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read of a deleted bundle failed: %+v", diags)
+	}
+	if d.Id() != "" {
+		t.Error("Read did not remove a deleted bundle from state")
+	}
+}
+
+func TestClientBundleReadDeletedPublicKey(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKeys, mockUsername): mockReturnJSON(client.GetKeysResponse{
+			AccountPubKeys: []client.AccountPublicKey{
+				{ID: "OTHER", PublicKey: "otherpublickey"},
+			},
+		}),
+	})
+
+	d := clientBundleResourceData(t, map[string]interface{}{
+		"public_key": "mypublickey",
+	})
+
+	if diags := connect.ResourceClientBundle().ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read of a deleted bundle failed: %+v", diags)
+	}
+	if d.Id() != "" {
+		t.Error("Read did not remove a deleted bundle from state")
+	}
+}
+
+func TestClientBundleReadServerError(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, mockUsername, "MINE"): mockReturnStatus(http.StatusInternalServerError),
+	})
+
+	d := clientBundleResourceData(t, map[string]interface{}{
+		"public_key":    "mypublickey",
+		"public_key_id": "MINE",
+	})
+
+	if diags := connect.ResourceClientBundle().ReadContext(context.Background(), d, c); !diags.HasError() {
+		t.Error("Read did not report a server error")
+	}
+	if d.Id() == "" {
+		t.Error("Read removed a bundle from state because of a server error")
+	}
+}
+
+func TestClientBundleReadUnreachable(t *testing.T) {
+	c, svr := mockClient(t, mockHandlerMap{})
+	svr.Close()
+
+	d := clientBundleResourceData(t, map[string]interface{}{
+		"public_key":    "mypublickey",
+		"public_key_id": "MINE",
+	})
+
+	if diags := connect.ResourceClientBundle().ReadContext(context.Background(), d, c); !diags.HasError() {
+		t.Error("Read did not report a connectivity failure")
+	}
+	if d.Id() == "" {
+		t.Error("Read removed a bundle from state because MKE could not be reached")
+	}
+}
+
+func TestClientBundleImport(t *testing.T) {
+	cert := mockCertificate(t, mockUsername)
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, mockUsername, "MINE"): mockReturnJSON(client.AccountPublicKey{
+			ID:           "MINE",
+			Label:        "mybundle",
+			PublicKey:    "mypublickey",
+			Certificates: []client.Certificate{{Label: "mybundle", Cert: cert}},
+		}),
+	})
+
+	r := connect.ResourceClientBundle()
+	d := r.TestResourceData()
+	d.SetId(mockUsername + "/MINE")
+
+	ds, err := r.Importer.StateContext(context.Background(), d, c)
+	if err != nil {
+		t.Fatalf("Import failed: %s", err)
+	}
+	if len(ds) != 1 {
+		t.Fatalf("Import returned %d resources", len(ds))
+	}
+	d = ds[0]
+
+	if d.Get("name").(string) != "mybundle" || d.Get("public_key").(string) != "mypublickey" || d.Get("public_key_id").(string) != "MINE" {
+		t.Errorf("Import set the wrong public key attributes: %+v", d.State())
+	}
+	if d.Get("client_cert").(string) != cert || d.Get("certificate.0.common_name").(string) != mockUsername {
+		t.Errorf("Import set the wrong certificate attributes: %+v", d.State())
+	}
+	if d.Get("private_key").(string) != "" {
+		t.Error("Import set a private key")
+	}
+}
+
+func TestClientBundleImportInvalidID(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{})
+	r := connect.ResourceClientBundle()
+
+	for _, id := range []string{"mybundle", "myuser/", "/MINE", "myuser/MINE/extra"} {
+		d := r.TestResourceData()
+		d.SetId(id)
+
+		if _, err := r.Importer.StateContext(context.Background(), d, c); !errors.Is(err, connect.ErrCBInvalidImport) {
+			t.Errorf("Wrong error importing %q: %s", id, err)
+		}
+	}
+}
+
+func TestClientBundleReadForAccount(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, "myservice", "SERVICEKEY"): mockReturnJSON(client.AccountPublicKey{
+			ID:        "SERVICEKEY",
+			Label:     "mybundle",
+			PublicKey: "mypublickey",
+		}),
+	})
+
+	d := clientBundleResourceData(t, map[string]interface{}{
+		"account":       "myservice",
+		"public_key":    "mypublickey",
+		"public_key_id": "SERVICEKEY",
+	})
+
+	if diags := connect.ResourceClientBundle().ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read failed: %+v", diags)
+	}
+	if d.Id() == "" {
+		t.Fatal("Read removed a bundle of another account that exists")
+	}
+}
+
+func TestClientBundleCreateForAccountNeedsPassword(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{})
+
+	r := connect.ResourceClientBundle()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":    "mybundle",
+		"account": "myservice",
+	})
+
+	diags := r.CreateContext(context.Background(), d, c)
+	if !diags.HasError() {
+		t.Fatal("Create for another account without a password did not fail")
+	}
+	if d.Id() != "" {
+		t.Error("Create for another account without a password set an ID")
+	}
+}
+
+func TestClientBundleCreatePublicKeyNotFound(t *testing.T) {
+	handlers := mockClientBundleHandlers(t)
+	handlers[http.MethodGet+" "+fmt.Sprintf(client.URLTargetPatternForPublicKeys, mockUsername)] = mockReturnJSON(client.GetKeysResponse{})
+	c, _ := mockClient(t, handlers)
+
+	r := connect.ResourceClientBundle()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name": "mybundle",
+	})
+
+	if diags := r.CreateContext(context.Background(), d, c); !diags.HasError() {
+		t.Error("Create did not report that the public key could not be found")
+	}
+	if d.Id() != "" {
+		t.Errorf("Create set an ID without a public key ID: %s", d.Id())
+	}
+}
+
+func TestClientBundleDeleteFailure(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodDelete + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, mockUsername, "MINE"): mockReturnStatus(http.StatusInternalServerError),
+	})
+
+	d := clientBundleResourceData(t, map[string]interface{}{
+		"public_key":    "mypublickey",
+		"public_key_id": "MINE",
+	})
+
+	if diags := connect.ResourceClientBundle().DeleteContext(context.Background(), d, c); !diags.HasError() {
+		t.Error("Delete did not report that MKE failed to delete the public key")
+	}
+	if d.Id() == "" {
+		t.Error("Delete removed a bundle from state which still exists in MKE")
+	}
+}
+
+func TestClientBundleDeleteAlreadyDeleted(t *testing.T) {
+	c, _ := mockClient(t, mockHandlerMap{
+		http.MethodDelete + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, mockUsername, "MINE"): mockReturnStatus(http.StatusNotFound),
+	})
+
+	d := clientBundleResourceData(t, map[string]interface{}{
+		"public_key":    "mypublickey",
+		"public_key_id": "MINE",
+	})
+
+	if diags := connect.ResourceClientBundle().DeleteContext(context.Background(), d, c); diags.HasError() {
+		t.Errorf("Delete of a bundle which was already deleted failed: %+v", diags)
+	}
+	if d.Id() != "" {
+		t.Error("Delete did not remove the bundle from state")
+	}
+}
+
+const (
+	mockKubeYml = `
+apiVersion: v1
+kind: Config
+clusters:
+- name: mycluster
+  cluster:
+    certificate-authority-data: RUZHSElK
+    server: https://localhost:6443
+contexts:
+- name: mycontext
+  context:
+    cluster: mycluster
+    user: myuser
+current-context: mycontext
+users:
+- name: myuser
+  user:
+    client-certificate-data: QUJDREU=
+    client-key-data: QkNERUZH
+`
+)
+
+// mockClientBundleHandlers a mock MKE API which generates a client bundle for
+// mockUsername, and holds its public key until it is deleted
+func mockClientBundleHandlers(t *testing.T) mockHandlerMap {
+	cert := mockCertificate(t, mockUsername)
+	cb := client.ClientBundle{
+		Files: map[string]string{
+			"ca.pem":   cert,
+			"cert.pem": cert,
+			"key.pem":  "myprivatekey",
+			"cert.pub": "mypublickey",
+			"kube.yml": mockKubeYml,
+			"env.sh":   "export DOCKER_HOST=tcp://localhost:443\n",
+		},
+	}
+	buf := new(bytes.Buffer)
+	if err := cb.WriteZip(buf); err != nil {
+		t.Fatalf("Could not make a client bundle zip: %s", err)
+	}
+
+	key := client.AccountPublicKey{ID: "MINE", Label: "mybundle", PublicKey: "mypublickey"}
+	deleted := false
+
+	return mockHandlerMap{
+		http.MethodPost + " " + client.URLTargetForClientBundle: func(w http.ResponseWriter, r *http.Request) {
+			w.Write(buf.Bytes())
+		},
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKeys, mockUsername): func(w http.ResponseWriter, r *http.Request) {
+			page := client.GetKeysResponse{AccountPubKeys: []client.AccountPublicKey{}}
+			if !deleted {
+				page.AccountPubKeys = append(page.AccountPubKeys, key)
+			}
+			mockReturnJSON(page)(w, r)
+		},
+		http.MethodGet + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, mockUsername, "MINE"): func(w http.ResponseWriter, r *http.Request) {
+			if deleted {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			mockReturnJSON(key)(w, r)
+		},
+		http.MethodDelete + " " + fmt.Sprintf(client.URLTargetPatternForPublicKey, mockUsername, "MINE"): func(w http.ResponseWriter, r *http.Request) {
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}
+}
+
+func TestClientBundleOutputPath(t *testing.T) {
+	c, _ := mockClient(t, mockClientBundleHandlers(t))
+	dir := filepath.Join(t.TempDir(), "bundle")
+
+	r := connect.ResourceClientBundle()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":        "mybundle",
+		"output_path": dir,
+	})
+
+	if diags := r.CreateContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Create failed: %+v", diags)
+	}
+	if d.Id() != mockUsername+"/MINE" {
+		t.Errorf("Create did not use the import ID format: %s", d.Id())
+	}
+	if info, err := os.Stat(filepath.Join(dir, "key.pem")); err != nil || info.Mode().Perm() != client.ClientBundlePrivateFileMode {
+		t.Fatalf("Create did not write the private key: %v, %s", info, err)
+	}
+
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read failed: %+v", diags)
+	}
+	if d.Get("output_path_drift").(bool) {
+		t.Error("Read found drift in files which were not changed")
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, "env.sh"), []byte("changed"), client.ClientBundleFileMode)
+
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read failed: %+v", diags)
+	}
+	if !d.Get("output_path_drift").(bool) {
+		t.Error("Read did not detect changed files")
+	}
+	if d.Get("output_path").(string) != dir {
+		t.Errorf("Read changed the configured output path: %s", d.Get("output_path"))
+	}
+
+	if diags := r.DeleteContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Delete failed: %+v", diags)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "key.pem")); !os.IsNotExist(err) {
+		t.Errorf("Delete after drift left the private key on disk: %s", err)
+	}
+}
+
+func TestClientBundleOutputPathWriteFailure(t *testing.T) {
+	c, _ := mockClient(t, mockClientBundleHandlers(t))
+
+	// a file where a directory is needed can't be written into
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	ioutil.WriteFile(blocker, []byte{}, client.ClientBundleFileMode)
+
+	r := connect.ResourceClientBundle()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":        "mybundle",
+		"output_path": filepath.Join(blocker, "bundle"),
+	})
+
+	if diags := r.CreateContext(context.Background(), d, c); !diags.HasError() {
+		t.Fatal("Create did not report that the files could not be written")
+	}
+	if d.Id() == "" || d.Get("public_key_id").(string) != "MINE" {
+		t.Errorf("Create which failed to write the files lost track of the bundle: %+v", d.State())
+	}
+}
+
+func TestClientBundleOutputPathDriftPlansUpdate(t *testing.T) {
+	r := connect.ResourceClientBundle()
+	state := &terraform.InstanceState{
+		ID: "mybundle",
+		Attributes: map[string]string{
+			"id":                "mybundle",
+			"name":              "mybundle",
+			"output_path":       "/tmp/bundle",
+			"output_path_drift": "true",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":        "mybundle",
+		"output_path": "/tmp/bundle",
+	})
+
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %s", err)
+	}
+	if diff == nil || diff.Attributes["output_path_drift"] == nil {
+		t.Fatalf("Drifted files did not plan a change: %+v", diff)
+	}
+	if diff.RequiresNew() {
+		t.Error("Drifted files planned a new client bundle")
+	}
+}
+
+// clientBundleRotationDiff plan a bundle in state whose certificate is valid
+// from notBefore to notAfter against a configuration
+func clientBundleRotationDiff(t *testing.T, notBefore, notAfter time.Time, state, config map[string]interface{}) (*terraform.InstanceDiff, error) {
+	attrs := map[string]string{
+		"id":                       "mybundle",
+		"name":                     "mybundle",
+		"ready_for_renewal":        "false",
+		"certificate.#":            "1",
+		"certificate.0.not_before": notBefore.UTC().Format(time.RFC3339),
+		"certificate.0.not_after":  notAfter.UTC().Format(time.RFC3339),
+	}
+	for key, val := range state {
+		attrs[key] = val.(string)
+	}
+	config["name"] = "mybundle"
+
+	return connect.ResourceClientBundle().Diff(
+		context.Background(),
+		&terraform.InstanceState{ID: "mybundle", Attributes: attrs},
+		terraform.NewResourceConfigRaw(config),
+		nil,
+	)
+}
+
+func TestClientBundleRotation(t *testing.T) {
+	now := time.Now()
+	lifetime := 90 * 24 * time.Hour
+
+	for _, tc := range []struct {
+		name     string
+		notAfter time.Time
+		state    map[string]interface{}
+		config   map[string]interface{}
+		replace  bool
+	}{
+		{
+			name:     "inside the window",
+			notAfter: now.Add(10 * time.Hour),
+			state:    map[string]interface{}{"rotate_before": "24h"},
+			config:   map[string]interface{}{"rotate_before": "24h"},
+			replace:  true,
+		},
+		{
+			name:     "window added in this plan",
+			notAfter: now.Add(10 * time.Hour),
+			state:    map[string]interface{}{},
+			config:   map[string]interface{}{"rotate_before": "24h"},
+			replace:  true,
+		},
+		{
+			name:     "outside the window",
+			notAfter: now.Add(100 * time.Hour),
+			state:    map[string]interface{}{"rotate_before": "24h"},
+			config:   map[string]interface{}{"rotate_before": "24h"},
+			replace:  false,
+		},
+		{
+			name:     "expired without a window",
+			notAfter: now.Add(-time.Hour),
+			state:    map[string]interface{}{},
+			config:   map[string]interface{}{},
+			replace:  true,
+		},
+		{
+			name:     "keepers changed",
+			notAfter: now.Add(100 * time.Hour),
+			state:    map[string]interface{}{"keepers.%": "1", "keepers.rotation": "2022-q1"},
+			config:   map[string]interface{}{"keepers": map[string]interface{}{"rotation": "2022-q2"}},
+			replace:  true,
+		},
+		{
+			name:     "keepers unchanged",
+			notAfter: now.Add(100 * time.Hour),
+			state:    map[string]interface{}{"keepers.%": "1", "keepers.rotation": "2022-q1"},
+			config:   map[string]interface{}{"keepers": map[string]interface{}{"rotation": "2022-q1"}},
+			replace:  false,
+		},
+	} {
+		diff, err := clientBundleRotationDiff(t, tc.notAfter.Add(-lifetime), tc.notAfter, tc.state, tc.config)
+		if err != nil {
+			t.Errorf("%s: diff failed: %s", tc.name, err)
+			continue
+		}
+		if replace := diff != nil && diff.RequiresNew(); replace != tc.replace {
+			t.Errorf("%s: planned replacement %t, expected %t: %+v", tc.name, replace, tc.replace, diff)
+		}
+	}
+}
+
+func TestClientBundleRotateBeforeLongerThanLifetime(t *testing.T) {
+	now := time.Now()
+
+	_, err := clientBundleRotationDiff(t, now.Add(-time.Hour), now.Add(24*time.Hour), map[string]interface{}{}, map[string]interface{}{
+		"rotate_before": "48h",
+	})
+	if !errors.Is(err, connect.ErrCBRotateBefore) {
+		t.Errorf("Wrong error for a rotate_before longer than the certificate lifetime: %s", err)
+	}
+}
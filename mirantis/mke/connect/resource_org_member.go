@@ -0,0 +1,160 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ResourceOrgMember for managing the membership of a user in an MKE organization
+func ResourceOrgMember() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceOrgMemberCreate,
+		ReadContext:   resourceOrgMemberRead,
+		UpdateContext: resourceOrgMemberUpdate,
+		DeleteContext: resourceOrgMemberDelete,
+		Schema: map[string]*schema.Schema{
+			"org": {
+				Type:        schema.TypeString,
+				Description: "Name of the organization.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Description: "Username of the member.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"admin": {
+				Type:        schema.TypeBool,
+				Description: "Is the user an admin of the organization.",
+				Optional:    true,
+				Default:     false,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceOrgMemberCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	org, user := d.Get("org").(string), d.Get("user").(string)
+
+	member, err := c.ApiOrgMemberAdd(ctx, org, user, client.SetMembership{IsAdmin: d.Get("admin").(bool)})
+	if err != nil {
+		diags = append(diags, diag.Errorf("MKE Client could not add the organization member: %s", err)...)
+		return diags
+	}
+
+	d.SetId(joinID(org, user))
+
+	diags = append(diags, resourceOrgMemberSet(d, org, user, member)...)
+
+	return diags
+}
+
+func resourceOrgMemberRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	parts, ok := splitID(d.Id(), 2)
+	if !ok {
+		diags = append(diags, diag.FromErr(fmt.Errorf("%w; organization member IDs are org/user, got %q", ErrInvalidID, d.Id()))...)
+		return diags
+	}
+	org, user := parts[0], parts[1]
+
+	member, err := c.ApiOrgMemberRetrieve(ctx, org, user)
+	if errors.Is(err, client.ErrUnknownTarget) {
+		// the user is no longer a member, so the membership should be removed from state
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+		return diags
+	}
+
+	diags = append(diags, resourceOrgMemberSet(d, org, user, member)...)
+
+	return diags
+}
+
+func resourceOrgMemberUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if d.HasChange("admin") {
+		org, user := d.Get("org").(string), d.Get("user").(string)
+
+		member, err := c.ApiOrgMemberAdd(ctx, org, user, client.SetMembership{IsAdmin: d.Get("admin").(bool)})
+		if err != nil {
+			diags = append(diags, diag.Errorf("MKE Client could not update the organization member: %s", err)...)
+			return diags
+		}
+		diags = append(diags, resourceOrgMemberSet(d, org, user, member)...)
+	}
+
+	return diags
+}
+
+func resourceOrgMemberDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if err := c.ApiOrgMemberDelete(ctx, d.Get("org").(string), d.Get("user").(string)); err != nil && !errors.Is(err, client.ErrUnknownTarget) {
+		diags = append(diags, diag.Errorf("MKE Client could not remove the organization member: %s", err)...)
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourceOrgMemberSet set the attributes from an MKE organization membership
+func resourceOrgMemberSet(d *schema.ResourceData, org, user string, member client.Member) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	attrs := map[string]interface{}{
+		"org":   org,
+		"user":  user,
+		"admin": member.IsAdmin,
+	}
+
+	for attr, val := range attrs {
+		if err := d.Set(attr, val); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,71 @@
+package connect_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	connect "github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/connect"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// mockOrgMemberHandlers a mock MKE API for the membership of "alice" in "myorg"
+func mockOrgMemberHandlers(member *client.Member) mockHandlerMap {
+	target := fmt.Sprintf(client.URLTargetPatternForOrgMember, "myorg", "alice")
+	create := func(r *http.Request) bool {
+		var membership client.SetMembership
+		if err := json.NewDecoder(r.Body).Decode(&membership); err != nil {
+			return false
+		}
+		*member = client.Member{Member: client.Account{Name: "alice"}, IsAdmin: membership.IsAdmin}
+		return true
+	}
+
+	return mockObjectHandlers(member, http.MethodPut, target, target, create, nil)
+}
+
+func TestOrgMemberLifecycle(t *testing.T) {
+	member := client.Member{}
+	c, _ := mockClient(t, mockOrgMemberHandlers(&member))
+
+	config := map[string]interface{}{
+		"org":   "myorg",
+		"user":  "alice",
+		"admin": true,
+	}
+	drift := func() {
+		if !member.IsAdmin {
+			t.Errorf("Create did not add an org admin: %+v", member)
+		}
+		member.IsAdmin = false
+	}
+	testMockLifecycle(t, c, connect.ResourceOrgMember(), config, "myorg/alice", drift, func(d *schema.ResourceData) {
+		if d.Get("admin").(bool) {
+			t.Errorf("Read did not detect drift: %+v", d.State())
+		}
+	})
+}
+
+func TestOrgMemberImport(t *testing.T) {
+	member := client.Member{Member: client.Account{Name: "alice"}, IsAdmin: true}
+	c, _ := mockClient(t, mockOrgMemberHandlers(&member))
+
+	r := connect.ResourceOrgMember()
+	d := r.TestResourceData()
+	d.SetId("myorg/alice")
+
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read of an imported member failed: %+v", diags)
+	}
+	if d.Get("org").(string) != "myorg" || d.Get("user").(string) != "alice" || !d.Get("admin").(bool) {
+		t.Errorf("Read set the wrong attributes: %+v", d.State())
+	}
+
+	member = client.Member{}
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() || d.Id() != "" {
+		t.Errorf("Read did not remove a removed member from state: %+v", diags)
+	}
+}
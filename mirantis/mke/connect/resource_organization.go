@@ -0,0 +1,149 @@
+package connect
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ResourceOrganization for managing MKE organizations
+func ResourceOrganization() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceOrganizationCreate,
+		ReadContext:   resourceOrganizationRead,
+		UpdateContext: resourceOrganizationUpdate,
+		DeleteContext: resourceOrganizationDelete,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Description: "Name of the organization.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"full_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"org_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the organization in MKE.",
+				Computed:    true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceOrganizationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	org, err := c.ApiOrgCreate(ctx, d.Get("name").(string), d.Get("full_name").(string))
+	if err != nil {
+		diags = append(diags, diag.Errorf("MKE Client could not create the organization: %s", err)...)
+		return diags
+	}
+
+	d.SetId(d.Get("name").(string))
+
+	diags = append(diags, resourceOrganizationSet(d, org)...)
+
+	return diags
+}
+
+func resourceOrganizationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	org, err := c.ApiOrgRetrieve(ctx, d.Id())
+	if errors.Is(err, client.ErrUnknownTarget) {
+		// the organization was removed from MKE, so it should be removed from state
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+		return diags
+	}
+
+	diags = append(diags, resourceOrganizationSet(d, org)...)
+
+	return diags
+}
+
+// Only the full name of an organization can be changed
+func resourceOrganizationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if d.HasChange("full_name") {
+		org, err := c.ApiOrgUpdate(ctx, d.Id(), d.Get("full_name").(string))
+		if err != nil {
+			diags = append(diags, diag.Errorf("MKE Client could not update the organization: %s", err)...)
+			return diags
+		}
+		diags = append(diags, resourceOrganizationSet(d, org)...)
+	}
+
+	return diags
+}
+
+func resourceOrganizationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if err := c.ApiOrgDelete(ctx, d.Id()); err != nil && !errors.Is(err, client.ErrUnknownTarget) {
+		diags = append(diags, diag.Errorf("MKE Client could not delete the organization: %s", err)...)
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourceOrganizationSet set the attributes from an MKE organization account
+func resourceOrganizationSet(d *schema.ResourceData, org client.Account) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	attrs := map[string]interface{}{
+		"full_name": org.FullName,
+		"org_id":    org.ID,
+	}
+	if org.Name != "" {
+		attrs["name"] = org.Name
+	}
+
+	for attr, val := range attrs {
+		if err := d.Set(attr, val); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
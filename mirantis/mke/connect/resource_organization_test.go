@@ -0,0 +1,70 @@
+package connect_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	connect "github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/connect"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// mockOrgHandlers a mock MKE account API holding a single organization
+func mockOrgHandlers(org *client.Account) mockHandlerMap {
+	create := func(r *http.Request) bool {
+		var create client.CreateAccount
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil || !create.IsOrg {
+			return false
+		}
+		*org = client.Account{ID: "orgid", Name: create.Name, FullName: create.FullName, IsOrg: true}
+		return true
+	}
+
+	return mockObjectHandlers(org, http.MethodPost, client.URLTargetForAccounts, fmt.Sprintf(client.URLTargetPatternForAccount, "myorg"), create, nil)
+}
+
+func TestOrganizationLifecycle(t *testing.T) {
+	org := client.Account{}
+	c, _ := mockClient(t, mockOrgHandlers(&org))
+
+	config := map[string]interface{}{
+		"name":      "myorg",
+		"full_name": "My Org",
+	}
+	testMockLifecycle(t, c, connect.ResourceOrganization(), config, "myorg", func() {
+		org.FullName = "Renamed Org"
+	}, func(d *schema.ResourceData) {
+		if d.Get("org_id").(string) != "orgid" || d.Get("full_name").(string) != "Renamed Org" {
+			t.Errorf("Read did not detect drift: %+v", d.State())
+		}
+	})
+}
+
+func TestOrganizationDataSource(t *testing.T) {
+	org := client.Account{ID: "orgid", Name: "myorg", FullName: "My Org", IsOrg: true}
+	c, _ := mockClient(t, mockOrgHandlers(&org))
+
+	ds := connect.DataSourceOrganization()
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"name": "myorg",
+	})
+
+	if diags := ds.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read failed: %+v", diags)
+	}
+	if d.Id() != "orgid" || d.Get("org_id").(string) != "orgid" || d.Get("full_name").(string) != "My Org" {
+		t.Errorf("Data source set the wrong attributes: %+v", d.State())
+	}
+
+	// a user is not an organization
+	org.IsOrg = false
+	d = schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"name": "myorg",
+	})
+	if diags := ds.ReadContext(context.Background(), d, c); !diags.HasError() {
+		t.Error("Data source found a user as an organization")
+	}
+}
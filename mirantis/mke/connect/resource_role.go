@@ -0,0 +1,155 @@
+package connect
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ResourceRole for managing custom MKE RBAC roles
+// MKE can't change a role, so any change replaces it.
+func ResourceRole() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRoleCreate,
+		ReadContext:   resourceRoleRead,
+		DeleteContext: resourceRoleDelete,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Description: "Name of the role.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"operations": {
+				Type:        schema.TypeSet,
+				Description: "Operations that the role allows, such as \"Container View\".",
+				Required:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(client.KnownRoleOperationNames(), false),
+				},
+			},
+
+			"role_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the role in MKE.",
+				Computed:    true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	names := []string{}
+	for _, item := range d.Get("operations").(*schema.Set).List() {
+		names = append(names, item.(string))
+	}
+	operations, err := client.NewRoleOperations(names)
+	if err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+		return diags
+	}
+
+	role, err := c.ApiRoleCreate(ctx, d.Get("name").(string), operations)
+	if err != nil {
+		diags = append(diags, diag.Errorf("MKE Client could not create the role: %s", err)...)
+		return diags
+	}
+
+	if role.ID != "" {
+		d.SetId(role.ID)
+	} else {
+		d.SetId(d.Get("name").(string))
+	}
+
+	diags = append(diags, resourceRoleSet(d, role)...)
+
+	return diags
+}
+
+func resourceRoleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	role, err := c.ApiRoleRetrieve(ctx, d.Id())
+	if errors.Is(err, client.ErrUnknownTarget) {
+		// the role was removed from MKE, so it should be removed from state
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+		return diags
+	}
+
+	diags = append(diags, resourceRoleSet(d, role)...)
+
+	return diags
+}
+
+func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if err := c.ApiRoleDelete(ctx, d.Id()); err != nil && !errors.Is(err, client.ErrUnknownTarget) {
+		diags = append(diags, diag.Errorf("MKE Client could not delete the role: %s", err)...)
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourceRoleSet set the attributes from an MKE role
+// Operations which MKE returns are kept even if they are not known, so that
+// they show up as a diff rather than being dropped.
+func resourceRoleSet(d *schema.ResourceData, role client.Role) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	operations := []interface{}{}
+	for _, name := range role.Operations.Names() {
+		operations = append(operations, name)
+	}
+
+	attrs := map[string]interface{}{
+		"role_id":    role.ID,
+		"operations": schema.NewSet(schema.HashString, operations),
+	}
+	if role.Name != "" {
+		attrs["name"] = role.Name
+	}
+
+	for attr, val := range attrs {
+		if err := d.Set(attr, val); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,59 @@
+package connect_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	connect "github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/connect"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// mockRoleHandlers a mock MKE role API holding a single role
+func mockRoleHandlers(role *client.Role) mockHandlerMap {
+	create := func(r *http.Request) bool {
+		var create client.Role
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil || create.Name == "" {
+			return false
+		}
+		*role = client.Role{ID: "roleid", Name: create.Name, Operations: create.Operations}
+		return true
+	}
+
+	return mockObjectHandlers(role, http.MethodPost, client.URLTargetForRoles, fmt.Sprintf(client.URLTargetPatternForRole, "roleid"), create, nil)
+}
+
+func TestRoleLifecycle(t *testing.T) {
+	role := client.Role{}
+	c, _ := mockClient(t, mockRoleHandlers(&role))
+
+	config := map[string]interface{}{
+		"name":       "viewer",
+		"operations": []interface{}{"Container View", "Service View"},
+	}
+	drift := func() {
+		if len(role.Operations["Container"]) != 1 || len(role.Operations["Service"]) != 1 {
+			t.Errorf("Create sent the wrong role: %+v", role)
+		}
+		role.Operations["Secret"] = map[string][]string{"Secret View": {}}
+	}
+	testMockLifecycle(t, c, connect.ResourceRole(), config, "roleid", drift, func(d *schema.ResourceData) {
+		if !d.Get("operations").(*schema.Set).Contains("Secret View") {
+			t.Errorf("Read did not detect drift: %+v", d.State())
+		}
+	})
+}
+
+func TestRoleUnknownOperation(t *testing.T) {
+	r := connect.ResourceRole()
+
+	validate := r.Schema["operations"].Elem.(*schema.Schema).ValidateFunc
+	if _, errs := validate("Container View", "operations"); len(errs) > 0 {
+		t.Errorf("A known operation was rejected: %+v", errs)
+	}
+	if _, errs := validate("Container Teleport", "operations"); len(errs) == 0 {
+		t.Error("An unknown operation was accepted")
+	}
+}
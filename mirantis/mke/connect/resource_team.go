@@ -0,0 +1,173 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ResourceTeam for managing the teams of an MKE organization
+func ResourceTeam() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceTeamCreate,
+		ReadContext:   resourceTeamRead,
+		UpdateContext: resourceTeamUpdate,
+		DeleteContext: resourceTeamDelete,
+		Schema: map[string]*schema.Schema{
+			"org": {
+				Type:        schema.TypeString,
+				Description: "Name of the organization the team belongs to.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Description: "Name of the team.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"team_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the team in MKE.",
+				Computed:    true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceTeamCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	org := d.Get("org").(string)
+	team, err := c.ApiTeamCreate(ctx, org, client.CreateTeam{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+	})
+	if err != nil {
+		diags = append(diags, diag.Errorf("MKE Client could not create the team: %s", err)...)
+		return diags
+	}
+
+	d.SetId(joinID(org, d.Get("name").(string)))
+
+	diags = append(diags, resourceTeamSet(d, org, team)...)
+
+	return diags
+}
+
+func resourceTeamRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	parts, ok := splitID(d.Id(), 2)
+	if !ok {
+		diags = append(diags, diag.FromErr(fmt.Errorf("%w; team IDs are org/team, got %q", ErrInvalidID, d.Id()))...)
+		return diags
+	}
+	org, name := parts[0], parts[1]
+
+	team, err := c.ApiTeamRetrieve(ctx, org, name)
+	if errors.Is(err, client.ErrUnknownTarget) {
+		// the team or its organization was removed from MKE, so it should be removed from state
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+		return diags
+	}
+	if team.Name == "" {
+		team.Name = name
+	}
+
+	diags = append(diags, resourceTeamSet(d, org, team)...)
+
+	return diags
+}
+
+func resourceTeamUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if d.HasChange("description") {
+		org := d.Get("org").(string)
+		description := d.Get("description").(string)
+
+		team, err := c.ApiTeamUpdate(ctx, org, d.Get("name").(string), client.UpdateTeam{Description: &description})
+		if err != nil {
+			diags = append(diags, diag.Errorf("MKE Client could not update the team: %s", err)...)
+			return diags
+		}
+		diags = append(diags, resourceTeamSet(d, org, team)...)
+	}
+
+	return diags
+}
+
+func resourceTeamDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if err := c.ApiTeamDelete(ctx, d.Get("org").(string), d.Get("name").(string)); err != nil && !errors.Is(err, client.ErrUnknownTarget) {
+		diags = append(diags, diag.Errorf("MKE Client could not delete the team: %s", err)...)
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourceTeamSet set the attributes from an MKE team
+func resourceTeamSet(d *schema.ResourceData, org string, team client.Team) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	attrs := map[string]interface{}{
+		"org":         org,
+		"description": team.Description,
+		"team_id":     team.ID,
+	}
+	if team.Name != "" {
+		attrs["name"] = team.Name
+	}
+
+	for attr, val := range attrs {
+		if err := d.Set(attr, val); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,123 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ResourceTeamMember for managing a single membership of an MKE team
+// This is not authoritative: other members of the team are left alone.
+func ResourceTeamMember() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceTeamMemberCreate,
+		ReadContext:   resourceTeamMemberRead,
+		DeleteContext: resourceTeamMemberDelete,
+		Schema: map[string]*schema.Schema{
+			"org": {
+				Type:        schema.TypeString,
+				Description: "Name of the organization the team belongs to.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"team": {
+				Type:        schema.TypeString,
+				Description: "Name of the team.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Description: "Username of the member, who must already be a member of the organization.",
+				Required:    true,
+				ForceNew:    true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceTeamMemberCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	org, team, user := d.Get("org").(string), d.Get("team").(string), d.Get("user").(string)
+
+	if _, err := c.ApiTeamMemberAdd(ctx, org, team, user, client.SetMembership{}); err != nil {
+		diags = append(diags, diag.Errorf("MKE Client could not add the team member: %s", err)...)
+		return diags
+	}
+
+	d.SetId(joinID(org, team, user))
+
+	return diags
+}
+
+func resourceTeamMemberRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	parts, ok := splitID(d.Id(), 3)
+	if !ok {
+		diags = append(diags, diag.FromErr(fmt.Errorf("%w; team member IDs are org/team/user, got %q", ErrInvalidID, d.Id()))...)
+		return diags
+	}
+	org, team, user := parts[0], parts[1], parts[2]
+
+	if _, err := c.ApiTeamMemberRetrieve(ctx, org, team, user); errors.Is(err, client.ErrUnknownTarget) {
+		// the user is no longer a member, so the membership should be removed from state
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+		return diags
+	}
+
+	attrs := map[string]interface{}{
+		"org":  org,
+		"team": team,
+		"user": user,
+	}
+	for attr, val := range attrs {
+		if err := d.Set(attr, val); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+func resourceTeamMemberDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if err := c.ApiTeamMemberDelete(ctx, d.Get("org").(string), d.Get("team").(string), d.Get("user").(string)); err != nil && !errors.Is(err, client.ErrUnknownTarget) {
+		diags = append(diags, diag.Errorf("MKE Client could not remove the team member: %s", err)...)
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
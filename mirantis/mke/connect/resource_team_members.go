@@ -0,0 +1,191 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ResourceTeamMembers for managing the full membership of an MKE team
+// This is authoritative: any member who is not configured is removed from the
+// team. Don't combine it with mke_team_member for the same team.
+func ResourceTeamMembers() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceTeamMembersCreate,
+		ReadContext:   resourceTeamMembersRead,
+		UpdateContext: resourceTeamMembersUpdate,
+		DeleteContext: resourceTeamMembersDelete,
+		Schema: map[string]*schema.Schema{
+			"org": {
+				Type:        schema.TypeString,
+				Description: "Name of the organization the team belongs to.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"team": {
+				Type:        schema.TypeString,
+				Description: "Name of the team.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"members": {
+				Type:        schema.TypeSet,
+				Description: "Usernames of all of the members of the team.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceTeamMembersCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	org, team := d.Get("org").(string), d.Get("team").(string)
+
+	if err := resourceTeamMembersSync(ctx, c, org, team, d.Get("members").(*schema.Set)); err != nil {
+		diags = append(diags, diag.Errorf("MKE Client could not set the team members: %s", err)...)
+		return diags
+	}
+
+	d.SetId(joinID(org, team))
+
+	return append(diags, resourceTeamMembersRead(ctx, d, m)...)
+}
+
+func resourceTeamMembersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	parts, ok := splitID(d.Id(), 2)
+	if !ok {
+		diags = append(diags, diag.FromErr(fmt.Errorf("%w; team members IDs are org/team, got %q", ErrInvalidID, d.Id()))...)
+		return diags
+	}
+	org, team := parts[0], parts[1]
+
+	members, err := c.ApiTeamMemberList(ctx, org, team)
+	if errors.Is(err, client.ErrUnknownTarget) {
+		// the team was removed from MKE, so it should be removed from state
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+		return diags
+	}
+
+	names := []interface{}{}
+	for _, member := range members {
+		names = append(names, member.Member.Name)
+	}
+
+	attrs := map[string]interface{}{
+		"org":     org,
+		"team":    team,
+		"members": schema.NewSet(schema.HashString, names),
+	}
+	for attr, val := range attrs {
+		if err := d.Set(attr, val); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+func resourceTeamMembersUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if d.HasChange("members") {
+		if err := resourceTeamMembersSync(ctx, c, d.Get("org").(string), d.Get("team").(string), d.Get("members").(*schema.Set)); err != nil {
+			diags = append(diags, diag.Errorf("MKE Client could not set the team members: %s", err)...)
+			return diags
+		}
+	}
+
+	return append(diags, resourceTeamMembersRead(ctx, d, m)...)
+}
+
+// Deleting removes every member that terraform knows of from the team
+func resourceTeamMembersDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	org, team := d.Get("org").(string), d.Get("team").(string)
+	for _, member := range d.Get("members").(*schema.Set).List() {
+		if err := c.ApiTeamMemberDelete(ctx, org, team, member.(string)); err != nil && !errors.Is(err, client.ErrUnknownTarget) {
+			diags = append(diags, diag.Errorf("MKE Client could not remove %s from the team: %s", member, err)...)
+		}
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourceTeamMembersSync add and remove team members so that the team has
+// exactly the wanted members
+// The current members are listed, rather than taken from state, so that members
+// added outside of terraform are also removed.
+func resourceTeamMembersSync(ctx context.Context, c *client.Client, org, team string, want *schema.Set) error {
+	members, err := c.ApiTeamMemberList(ctx, org, team)
+	if err != nil {
+		return err
+	}
+
+	current := map[string]bool{}
+	for _, member := range members {
+		current[member.Member.Name] = true
+		if !want.Contains(member.Member.Name) {
+			if err := c.ApiTeamMemberDelete(ctx, org, team, member.Member.Name); err != nil && !errors.Is(err, client.ErrUnknownTarget) {
+				return fmt.Errorf("removing %s; %w", member.Member.Name, err)
+			}
+		}
+	}
+
+	for _, item := range want.List() {
+		name := item.(string)
+		if current[name] {
+			continue
+		}
+		if _, err := c.ApiTeamMemberAdd(ctx, org, team, name, client.SetMembership{}); err != nil {
+			return fmt.Errorf("adding %s; %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package connect_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	connect "github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/connect"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// mockTeamHandlers a mock MKE team API holding "myteam" in "myorg", and its
+// members by username
+func mockTeamHandlers(team *client.Team, members map[string]bool) mockHandlerMap {
+	teams := fmt.Sprintf(client.URLTargetPatternForTeams, "myorg")
+	target := fmt.Sprintf(client.URLTargetPatternForTeam, "myorg", "myteam")
+	membersTarget := fmt.Sprintf(client.URLTargetPatternForTeamMembers, "myorg", "myteam")
+
+	create := func(r *http.Request) bool {
+		var create client.CreateTeam
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil || create.Name != "myteam" {
+			return false
+		}
+		*team = client.Team{ID: "teamid", Name: create.Name, Description: create.Description}
+		return true
+	}
+	update := func(r *http.Request) bool {
+		var update client.UpdateTeam
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil || update.Description == nil {
+			return false
+		}
+		team.Description = *update.Description
+		return true
+	}
+
+	handlers := mockObjectHandlers(team, http.MethodPost, teams, target, create, update)
+	handlers[http.MethodGet+" "+membersTarget] = func(w http.ResponseWriter, r *http.Request) {
+		if team.ID == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		page := client.GetMembersResponse{Members: []client.Member{}}
+		for name := range members {
+			page.Members = append(page.Members, client.Member{Member: client.Account{Name: name}})
+		}
+		mockReturnJSON(page)(w, r)
+	}
+
+	// every user can be added, retrieved and removed
+	for _, name := range []string{"alice", "bob", "carol"} {
+		name := name
+		memberTarget := fmt.Sprintf(client.URLTargetPatternForTeamMember, "myorg", "myteam", name)
+
+		handlers[http.MethodPut+" "+memberTarget] = func(w http.ResponseWriter, r *http.Request) {
+			members[name] = true
+			mockReturnJSON(client.Member{Member: client.Account{Name: name}})(w, r)
+		}
+		handlers[http.MethodGet+" "+memberTarget] = func(w http.ResponseWriter, r *http.Request) {
+			if !members[name] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			mockReturnJSON(client.Member{Member: client.Account{Name: name}})(w, r)
+		}
+		handlers[http.MethodDelete+" "+memberTarget] = func(w http.ResponseWriter, r *http.Request) {
+			delete(members, name)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+
+	return handlers
+}
+
+// sortedMembers the mock team members, for comparison
+func sortedMembers(members map[string]bool) string {
+	names := []string{}
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func TestTeamLifecycle(t *testing.T) {
+	team := client.Team{}
+	c, _ := mockClient(t, mockTeamHandlers(&team, map[string]bool{}))
+
+	config := map[string]interface{}{
+		"org":         "myorg",
+		"name":        "myteam",
+		"description": "My team",
+	}
+	testMockLifecycle(t, c, connect.ResourceTeam(), config, "myorg/myteam", func() {
+		team.Description = "Renamed team"
+	}, func(d *schema.ResourceData) {
+		if d.Get("team_id").(string) != "teamid" || d.Get("description").(string) != "Renamed team" {
+			t.Errorf("Read did not detect drift: %+v", d.State())
+		}
+	})
+}
+
+func TestTeamImport(t *testing.T) {
+	team := client.Team{ID: "teamid", Name: "myteam", Description: "My team"}
+	c, _ := mockClient(t, mockTeamHandlers(&team, map[string]bool{}))
+
+	r := connect.ResourceTeam()
+	d := r.TestResourceData()
+	d.SetId("myorg/myteam")
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read of an imported team failed: %+v", diags)
+	}
+	if d.Get("org").(string) != "myorg" || d.Get("name").(string) != "myteam" || d.Get("description").(string) != "My team" {
+		t.Errorf("Read set the wrong attributes: %+v", d.State())
+	}
+
+	d.SetId("myteam")
+	if diags := r.ReadContext(context.Background(), d, c); !diags.HasError() {
+		t.Error("Read accepted an ID without the organization")
+	}
+}
+
+func TestTeamMembersAuthoritative(t *testing.T) {
+	team := client.Team{ID: "teamid", Name: "myteam"}
+	members := map[string]bool{"carol": true}
+	c, _ := mockClient(t, mockTeamHandlers(&team, members))
+
+	r := connect.ResourceTeamMembers()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"org":     "myorg",
+		"team":    "myteam",
+		"members": []interface{}{"alice", "bob"},
+	})
+
+	if diags := r.CreateContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Create failed: %+v", diags)
+	}
+	if got := sortedMembers(members); got != "alice,bob" {
+		t.Errorf("Create did not set exactly the configured members: %s", got)
+	}
+	if d.Id() != "myorg/myteam" || d.Get("members").(*schema.Set).Len() != 2 {
+		t.Errorf("Create set the wrong attributes: %+v", d.State())
+	}
+
+	// a member added outside of terraform is detected
+	members["carol"] = true
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Read failed: %+v", diags)
+	}
+	if !d.Get("members").(*schema.Set).Contains("carol") {
+		t.Errorf("Read did not detect an added member: %+v", d.State())
+	}
+
+	if diags := r.DeleteContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Delete failed: %+v", diags)
+	}
+	if len(members) != 0 {
+		t.Errorf("Delete did not remove the members: %s", sortedMembers(members))
+	}
+}
+
+func TestTeamMemberNonAuthoritative(t *testing.T) {
+	team := client.Team{ID: "teamid", Name: "myteam"}
+	members := map[string]bool{"carol": true}
+	c, _ := mockClient(t, mockTeamHandlers(&team, members))
+
+	r := connect.ResourceTeamMember()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"org":  "myorg",
+		"team": "myteam",
+		"user": "alice",
+	})
+
+	if diags := r.CreateContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Create failed: %+v", diags)
+	}
+	if d.Id() != "myorg/myteam/alice" {
+		t.Errorf("Create set the wrong ID: %s", d.Id())
+	}
+	if got := sortedMembers(members); got != "alice,carol" {
+		t.Errorf("Create changed other members: %s", got)
+	}
+
+	if diags := r.DeleteContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("Delete failed: %+v", diags)
+	}
+	if got := sortedMembers(members); got != "carol" {
+		t.Errorf("Delete changed other members: %s", got)
+	}
+
+	d = r.TestResourceData()
+	d.SetId("myorg/myteam/alice")
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() || d.Id() != "" {
+		t.Errorf("Read did not remove a removed member from state: %+v", diags)
+	}
+}
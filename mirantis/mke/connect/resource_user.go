@@ -0,0 +1,211 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var (
+	ErrUserIsOrg = errors.New("MKE account is an organization, not a user")
+)
+
+// ResourceUser for managing MKE user accounts
+func ResourceUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Description: "Username of the account.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"full_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Description: "Password of the account, which is set in MKE but never read back.",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"admin": {
+				Type:        schema.TypeBool,
+				Description: "Is the user an MKE admin.",
+				Optional:    true,
+				Default:     false,
+			},
+			"active": {
+				Type:        schema.TypeBool,
+				Description: "Can the user log in.",
+				Optional:    true,
+				Default:     true,
+			},
+
+			"account_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the account in MKE.",
+				Computed:    true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	account, err := c.ApiAccountCreate(ctx, client.CreateAccount{
+		Name:     d.Get("name").(string),
+		FullName: d.Get("full_name").(string),
+		Password: d.Get("password").(string),
+		IsAdmin:  d.Get("admin").(bool),
+		IsActive: d.Get("active").(bool),
+	})
+	if err != nil {
+		diags = append(diags, diag.Errorf("MKE Client could not create the user: %s", err)...)
+		return diags
+	}
+
+	d.SetId(d.Get("name").(string))
+
+	diags = append(diags, resourceUserSet(d, account)...)
+
+	return diags
+}
+
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	account, err := c.ApiAccountRetrieve(ctx, d.Id())
+	if errors.Is(err, client.ErrUnknownTarget) {
+		// the user was removed from MKE, so it should be removed from state
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+		return diags
+	}
+
+	if account.IsOrg {
+		diags = append(diags, diag.FromErr(fmt.Errorf("%w; %s", ErrUserIsOrg, d.Id()))...)
+		return diags
+	}
+
+	diags = append(diags, resourceUserSet(d, account)...)
+
+	return diags
+}
+
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if d.HasChanges("full_name", "admin", "active") {
+		update := client.UpdateAccount{}
+		if d.HasChange("full_name") {
+			fullName := d.Get("full_name").(string)
+			update.FullName = &fullName
+		}
+		if d.HasChange("admin") {
+			isAdmin := d.Get("admin").(bool)
+			update.IsAdmin = &isAdmin
+		}
+		if d.HasChange("active") {
+			isActive := d.Get("active").(bool)
+			update.IsActive = &isActive
+		}
+
+		account, err := c.ApiAccountUpdate(ctx, d.Id(), update)
+		if err != nil {
+			diags = append(diags, diag.Errorf("MKE Client could not update the user: %s", err)...)
+			return diags
+		}
+		diags = append(diags, resourceUserSet(d, account)...)
+	}
+
+	if d.HasChange("password") {
+		if err := c.ApiAccountChangePassword(ctx, d.Id(), d.Get("password").(string)); err != nil {
+			diags = append(diags, diag.Errorf("MKE Client could not change the user password: %s", err)...)
+
+			// keep the old password in state, so that the change is planned again,
+			// while the other changes which were made are kept
+			oldPassword, _ := d.GetChange("password")
+			if err := d.Set("password", oldPassword); err != nil {
+				diags = append(diags, diag.FromErr(err)...)
+			}
+		}
+	}
+
+	return diags
+}
+
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, ok := m.(*client.Client)
+	if !ok {
+		diags = append(diags, diag.Errorf("unable to cast meta interface to MKE Client")...)
+		return diags
+	}
+
+	if err := c.ApiAccountDelete(ctx, d.Id()); err != nil && !errors.Is(err, client.ErrUnknownTarget) {
+		diags = append(diags, diag.Errorf("MKE Client could not delete the user: %s", err)...)
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourceUserSet set the attributes from an MKE account
+func resourceUserSet(d *schema.ResourceData, account client.Account) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	attrs := map[string]interface{}{
+		"full_name":  account.FullName,
+		"admin":      account.IsAdmin,
+		"active":     account.IsActive,
+		"account_id": account.ID,
+	}
+	if account.Name != "" {
+		attrs["name"] = account.Name
+	}
+
+	for attr, val := range attrs {
+		if err := d.Set(attr, val); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,127 @@
+package connect_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/client"
+	connect "github.com/Mirantis/terraform-provider-mirantis/mirantis/mke/connect"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// mockUserHandlers a mock MKE account API holding a single user
+func mockUserHandlers(account *client.Account) mockHandlerMap {
+	create := func(r *http.Request) bool {
+		var create client.CreateAccount
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil || create.Password == "" || create.IsOrg {
+			return false
+		}
+		*account = client.Account{ID: "newid", Name: create.Name, FullName: create.FullName, IsAdmin: create.IsAdmin, IsActive: create.IsActive}
+		return true
+	}
+	update := func(r *http.Request) bool {
+		var update client.UpdateAccount
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			return false
+		}
+		if update.FullName != nil {
+			account.FullName = *update.FullName
+		}
+		if update.IsAdmin != nil {
+			account.IsAdmin = *update.IsAdmin
+		}
+		if update.IsActive != nil {
+			account.IsActive = *update.IsActive
+		}
+		return true
+	}
+
+	return mockObjectHandlers(account, http.MethodPost, client.URLTargetForAccounts, fmt.Sprintf(client.URLTargetPatternForAccount, "newuser"), create, update)
+}
+
+func TestUserLifecycle(t *testing.T) {
+	account := client.Account{}
+	c, _ := mockClient(t, mockUserHandlers(&account))
+
+	config := map[string]interface{}{
+		"name":      "newuser",
+		"full_name": "New User",
+		"password":  "newpassword",
+	}
+	drift := func() {
+		if !account.IsActive || account.IsAdmin {
+			t.Errorf("Create made the wrong account: %+v", account)
+		}
+		// changed in the MKE UI
+		account.FullName = "Renamed User"
+		account.IsAdmin = true
+	}
+	testMockLifecycle(t, c, connect.ResourceUser(), config, "newuser", drift, func(d *schema.ResourceData) {
+		if d.Get("account_id").(string) != "newid" || !d.Get("active").(bool) {
+			t.Errorf("Create set the wrong attributes: %+v", d.State())
+		}
+		if d.Get("full_name").(string) != "Renamed User" || !d.Get("admin").(bool) {
+			t.Errorf("Read did not detect drift: %+v", d.State())
+		}
+		if d.Get("password").(string) != "newpassword" {
+			t.Error("Read changed the password")
+		}
+	})
+}
+
+func TestUserIsOrg(t *testing.T) {
+	account := client.Account{ID: "orgid", Name: "newuser", IsOrg: true}
+	c, _ := mockClient(t, mockUserHandlers(&account))
+
+	r := connect.ResourceUser()
+	d := r.TestResourceData()
+	d.SetId("newuser")
+
+	if diags := r.ReadContext(context.Background(), d, c); !diags.HasError() {
+		t.Error("Read of an organization as a user did not fail")
+	}
+}
+
+func TestUserPasswordChangeRejected(t *testing.T) {
+	account := client.Account{ID: "newid", Name: "newuser", FullName: "New User", IsActive: true}
+	handlers := mockUserHandlers(&account)
+	handlers[http.MethodPost+" "+fmt.Sprintf(client.URLTargetPatternForAccountPassword, "newuser")] = mockReturnStatus(http.StatusBadRequest)
+	c, _ := mockClient(t, handlers)
+
+	r := connect.ResourceUser()
+	state := &terraform.InstanceState{
+		ID: "newuser",
+		Attributes: map[string]string{
+			"id":         "newuser",
+			"name":       "newuser",
+			"full_name":  "New User",
+			"password":   "oldpassword",
+			"admin":      "false",
+			"active":     "true",
+			"account_id": "newid",
+		},
+	}
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":      "newuser",
+		"full_name": "Renamed User",
+		"password":  "newpassword",
+	}), nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %s", err)
+	}
+
+	newState, diags := r.Apply(context.Background(), state, diff, c)
+	if !diags.HasError() {
+		t.Fatal("Update did not report the rejected password change")
+	}
+	if newState.Attributes["password"] != "oldpassword" {
+		t.Errorf("Rejected password was saved to state: %s", newState.Attributes["password"])
+	}
+	if newState.Attributes["full_name"] != "Renamed User" {
+		t.Errorf("Update which was applied was not saved to state: %s", newState.Attributes["full_name"])
+	}
+}